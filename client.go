@@ -1,50 +1,347 @@
 package typeGopher
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
 	"os"
+	"os/user"
+	"time"
 
-	tl "github.com/JoelOtter/termloop"
+	tl "gopher_typer/term"
 )
 
 // GopherTyper handles the local state of the game.
 type GopherTyper struct {
-	g        *tl.Game
-	wordList []string
-	intro    introLevel
-	game     gameLevel
-	store    storeLevel
-	end      endLevel
-	console  tl.Text
-	level    tl.Level
-	stats    stats
-	items    []item
+	g           *tl.Game
+	packs       []*wordPack
+	pack        *wordPack
+	wordPaths   []string
+	intro       introLevel
+	game        gameLevel
+	store       storeLevel
+	end         endLevel
+	leaderboard scoresLevel
+	rooms       lobbyLevel
+	console     tl.Text
+	level       tl.Level
+	stats       stats
+	levelsWon   int
+	items       []item
+	clock       *ScaledClock
+	speed       float64
+	rng         *rand.Rand
+	seed        int64
+	recorder    *replayRecorder
+	player      *Replay
+
+	savePath string
+	noSaving bool
+	saved    *saveGame
+
+	playerName string
+	scores     *ScoreBoard
+	metrics    *Metrics
+
+	configPath     string
+	difficultyName string
+	difficulty     difficulty
+
+	terminal tl.Terminal
+	lobby    *Lobby
+	racer    *racer
+	overlay  overlay
+}
+
+// Option configures a GopherTyper.
+type Option func(*GopherTyper)
+
+// WithClock makes the game read the time from c instead of the system clock.
+func WithClock(c Clock) Option {
+	return func(gt *GopherTyper) {
+		gt.clock = NewScaledClock(c)
+	}
+}
+
+// WithRand makes every random choice in the game come from r, so a run can be reproduced from its seed.
+func WithRand(r *rand.Rand) Option {
+	return func(gt *GopherTyper) {
+		gt.rng = r
+	}
+}
+
+// WithSeed seeds the game's random number generator, so a run can be reproduced and recorded.
+func WithSeed(seed int64) Option {
+	return func(gt *GopherTyper) {
+		gt.seed = seed
+		gt.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithRecording writes every key event of the session to w so it can be replayed later.
+func WithRecording(w io.Writer) Option {
+	return func(gt *GopherTyper) {
+		gt.recorder = &replayRecorder{enc: json.NewEncoder(w)}
+	}
+}
+
+// WithReplay plays back a recorded session instead of reading live input.
+func WithReplay(r *Replay) Option {
+	return func(gt *GopherTyper) {
+		gt.seed = r.header.Seed
+		gt.rng = rand.New(rand.NewSource(r.header.Seed))
+		r.clock = NewFakeClock(time.Unix(0, 0))
+		gt.clock = NewScaledClock(r.clock)
+		gt.player = r
+		gt.noSaving = !r.header.Saving
+		gt.saved = r.header.Save
+	}
+}
+
+// WithWords loads word packs from the given files or directories instead of the built-in ones.
+func WithWords(paths ...string) Option {
+	return func(gt *GopherTyper) {
+		gt.wordPaths = append(gt.wordPaths, paths...)
+	}
+}
+
+// WithConfig reads settings from the config file at path instead of the one in the user's config directory.
+func WithConfig(path string) Option {
+	return func(gt *GopherTyper) {
+		gt.configPath = path
+	}
+}
+
+// WithDifficulty plays with the named difficulty preset instead of the one in the config file.
+func WithDifficulty(name string) Option {
+	return func(gt *GopherTyper) {
+		gt.difficultyName = name
+	}
+}
+
+// WithSaveFile saves and resumes the game from the file at path instead of the one in the user's config directory.
+func WithSaveFile(path string) Option {
+	return func(gt *GopherTyper) {
+		gt.savePath = path
+	}
+}
+
+// WithoutSaving plays without reading or writing a save file.
+func WithoutSaving() Option {
+	return func(gt *GopherTyper) {
+		gt.noSaving = true
+	}
+}
+
+// WithPlayer names the player on the high score table instead of using the login name.
+func WithPlayer(name string) Option {
+	return func(gt *GopherTyper) {
+		gt.playerName = name
+	}
+}
+
+// WithScoreBoard records high scores on b, which may be shared with other games.
+func WithScoreBoard(b *ScoreBoard) Option {
+	return func(gt *GopherTyper) {
+		gt.scores = b
+	}
+}
+
+// WithTerminal runs the game on t instead of the terminal the process is attached to.
+func WithTerminal(t tl.Terminal) Option {
+	return func(gt *GopherTyper) {
+		gt.terminal = t
+	}
+}
+
+// WithLobby lets the player race others in the rooms of l.
+func WithLobby(l *Lobby) Option {
+	return func(gt *GopherTyper) {
+		gt.lobby = l
+	}
 }
 
 // NewGopherTyper gets the game ready to run.
-func NewGopherTyper() (*GopherTyper, error) {
-	wReader, err := os.Open("data/words.txt")
-	if err != nil {
+func NewGopherTyper(opts ...Option) (*GopherTyper, error) {
+	gt := GopherTyper{}
+	for _, opt := range opts {
+		opt(&gt)
+	}
+
+	if err := gt.loadConfig(); err != nil {
 		return nil, err
 	}
 
-	gt := GopherTyper{}
-	gt.g = tl.NewGame()
+	var err error
+	if len(gt.wordPaths) > 0 {
+		gt.packs, err = loadWords(gt.wordPaths)
+	} else {
+		gt.packs, err = loadDefaultWords()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(gt.packs) == 0 {
+		return nil, errors.New("no words to play with")
+	}
+	for _, p := range gt.packs {
+		if len(p.words) == 0 {
+			return nil, fmt.Errorf("word pack %q has no words", p.name)
+		}
+	}
+	gt.pack = gt.packs[0]
+	if gt.clock == nil {
+		gt.clock = NewScaledClock(systemClock{})
+	}
+	gt.speed = 1
+	if gt.rng == nil {
+		gt.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	if gt.terminal == nil {
+		gt.terminal = tl.NewTermbox()
+	}
+	gt.g = tl.NewGame(gt.terminal)
 	gt.g.Screen().SetFps(30)
-	gt.wordList = newWordLoader(wReader)
 	gt.intro = newIntroLevel(&gt, tl.ColorBlack, tl.ColorBlue)
 	gt.game = newGameLevel(&gt, tl.ColorBlack, tl.ColorRed)
 	gt.store = newStoreLevel(&gt, tl.ColorBlack, tl.ColorCyan)
 	gt.end = newEndLevel(&gt, tl.ColorBlack, tl.ColorGreen)
+	gt.leaderboard = newScoresLevel(&gt, tl.ColorBlack, tl.ColorBlue)
+	gt.rooms = newLobbyLevel(&gt, tl.ColorBlack, tl.ColorBlue)
+	gt.overlay.gt = &gt
 
 	gt.stats = newStats()
+	gt.metrics = newMetrics(time.Now())
+
+	if gt.savePath == "" && !gt.noSaving {
+		// Without a config directory there is nowhere to save, but the game can still be played.
+		gt.savePath, _ = defaultSavePath()
+	}
+	if gt.savePath != "" && gt.player == nil {
+		if gt.saved, err = readSave(gt.savePath); err != nil {
+			return nil, err
+		}
+	}
+
+	if gt.playerName == "" {
+		gt.playerName = defaultPlayerName()
+	}
+	if gt.scores == nil {
+		if path, err := DefaultScoresPath(); err == nil {
+			if gt.scores, err = OpenScoreBoard(path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if gt.recorder != nil {
+		if err := gt.recorder.start(&gt); err != nil {
+			return nil, err
+		}
+	}
+	if gt.player != nil {
+		gt.player.start(&gt)
+	}
 
 	return &gt, nil
 }
 
-// Run starts the game, and blocks forever.
-func (gt *GopherTyper) Run() {
+// loadConfig reads the config file and picks the difficulty preset to play with.
+func (gt *GopherTyper) loadConfig() error {
+	path, mustExist := gt.configPath, true
+	if path == "" {
+		var err error
+		if path, err = defaultConfigPath(); err != nil {
+			return err
+		}
+		mustExist = false
+	}
+	cfg, err := loadConfig(path, mustExist)
+	if err != nil {
+		return err
+	}
+
+	name := cfg.difficulty
+	if gt.difficultyName != "" {
+		name = gt.difficultyName
+	}
+	gt.difficulty, err = cfg.preset(name)
+	if err != nil {
+		return err
+	}
+	if gt.player != nil && gt.player.header.Difficulty != nil {
+		gt.difficulty = *gt.player.header.Difficulty
+	}
+	return nil
+}
+
+// Run starts the game, and blocks until the player quits or Stop is called.
+func (gt *GopherTyper) Run() error {
 	gt.goToIntro()
-	gt.g.Start()
+	if gt.player != nil {
+		gt.g.Screen().AddEntity(gt.player)
+	}
+	gt.g.Screen().AddEntity(&gt.overlay)
+	defer gt.leaveRace()
+	return gt.g.Start()
+}
+
+// Stop ends a running game. It is safe to call from any goroutine.
+func (gt *GopherTyper) Stop() {
+	gt.g.Stop()
+}
+
+// Metrics returns the typing statistics collected so far this session.
+func (gt *GopherTyper) Metrics() *Metrics {
+	return gt.metrics
+}
+
+// SetSpeed changes how fast game time passes, e.g. 0.5 for half speed.
+// Races are always played at full speed.
+func (gt *GopherTyper) SetSpeed(speed float64) {
+	gt.setSpeed(speed)
+}
+
+// setSpeed changes the game speed, keeping the run off the high score table
+// if it is slowed down.
+func (gt *GopherTyper) setSpeed(speed float64) {
+	gt.speed = speed
+	if speed < 1 {
+		gt.stats.SlowedDown = true
+	}
+	gt.clock.SetSpeed(speed)
+}
+
+// Pause freezes game time, stopping falling words, goroutines and garbage collection.
+func (gt *GopherTyper) Pause() {
+	gt.clock.Pause()
+}
+
+// Resume restarts game time after Pause.
+func (gt *GopherTyper) Resume() {
+	gt.clock.Resume()
+}
+
+// handleInput records key events when recording and reports whether the current level
+// should act on e. Live input is ignored while a replay is playing.
+func (gt *GopherTyper) handleInput(e tl.Event) bool {
+	if gt.player != nil && !gt.player.delivering {
+		return false
+	}
+	if gt.recorder != nil && e.Type == tl.EventKey {
+		gt.recorder.record(gt, e)
+	}
+	return true
+}
+
+// fieldSize returns the size of the playing field, which is the recorded one during a replay.
+func (gt *GopherTyper) fieldSize() (int, int) {
+	if gt.player != nil && gt.player.width > 0 {
+		return gt.player.width, gt.player.height
+	}
+	return gt.g.Screen().Size()
 }
 
 // goToIntro sets the current level to the intro and activates it.
@@ -53,12 +350,53 @@ func (gt *GopherTyper) goToIntro() {
 	gt.intro.Activate()
 }
 
+// recordScore updates the player's personal bests on the high score table.
+// Replays and slowed down runs never touch it.
+func (gt *GopherTyper) recordScore() {
+	if gt.scores == nil || gt.player != nil || gt.stats.SlowedDown {
+		return
+	}
+	_, err := gt.scores.Record(Score{
+		Player:       gt.playerName,
+		HighestLevel: gt.stats.LevelsCompleted,
+		TotalEarned:  gt.stats.TotalEarned,
+		WPM:          gt.metrics.WPM(),
+		Accuracy:     gt.metrics.Accuracy(),
+		UpdatedAt:    time.Now(),
+	})
+	if err != nil {
+		gt.console.SetText(fmt.Sprintf("Err: %+v", err))
+	}
+}
+
+// defaultPlayerName returns the login name of the user running the game.
+func defaultPlayerName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "player"
+}
+
+// continueGame restores the saved game and starts its next level.
+func (gt *GopherTyper) continueGame() {
+	if err := gt.restore(gt.saved); err != nil {
+		gt.console.SetText(fmt.Sprintf("Err: %+v", err))
+		return
+	}
+	gt.goToGame()
+}
+
 // goToGame sets the current level to the game level and activates it.
 func (gt *GopherTyper) goToGame() {
 	if gt.stats.Lives == 0 {
 		gt.stats = newStats()
+		gt.metrics = newMetrics(time.Now())
 		gt.items = []item{}
 	}
+	gt.game.racer = nil
 	gt.level = &gt.game
 	gt.game.Activate()
 }
@@ -69,6 +407,44 @@ func (gt *GopherTyper) goToStore() {
 	gt.store.Activate()
 }
 
+// goToScores sets the current level to the high score table and activates it.
+func (gt *GopherTyper) goToScores() {
+	gt.level = &gt.leaderboard
+	gt.leaderboard.Activate()
+}
+
+// goToLobby sets the current level to the race lobby and activates it.
+func (gt *GopherTyper) goToLobby() {
+	gt.rooms.finished = false
+	gt.level = &gt.rooms
+	gt.rooms.Activate()
+}
+
+// goToRace sets the current level to the game level, playing the race in the player's room.
+func (gt *GopherTyper) goToRace() {
+	gt.game.racer = gt.racer
+	gt.level = &gt.game
+	gt.game.Activate()
+}
+
+// goToRaceResults sets the current level to the standings of the player's race and activates it.
+func (gt *GopherTyper) goToRaceResults() {
+	// The race's keystrokes are already in the metrics, so its time must be too
+	gt.metrics.level(gt.game.game.Result().Duration)
+	gt.game.racer = nil
+	gt.rooms.finished = true
+	gt.level = &gt.rooms
+	gt.rooms.Activate()
+}
+
+// leaveRace takes the player out of their race room, if they are in one.
+func (gt *GopherTyper) leaveRace() {
+	if gt.racer != nil {
+		gt.lobby.leave(gt.racer)
+		gt.racer = nil
+	}
+}
+
 // goToEndWin sets the current level to the end level with a win condition and activates it.
 func (gt *GopherTyper) goToEndWin() {
 	gt.level = &gt.end
@@ -0,0 +1,104 @@
+package typeGopher
+
+import "time"
+
+// Clock tells the game what time it is.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+// Now returns the current wall clock time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock is a Clock that only moves when told to, for tests and replays.
+type FakeClock struct {
+	now time.Time
+}
+
+// NewFakeClock creates a FakeClock stopped at the given time.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the fake clock's current time.
+func (c *FakeClock) Now() time.Time {
+	return c.now
+}
+
+// Advance moves the fake clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// Set moves the fake clock to t.
+func (c *FakeClock) Set(t time.Time) {
+	c.now = t
+}
+
+// ScaledClock wraps another Clock so game time can be paused or run at a different speed.
+type ScaledClock struct {
+	base    Clock
+	speed   float64
+	paused  bool
+	anchor  time.Time
+	elapsed time.Duration
+	start   time.Time
+}
+
+// NewScaledClock creates a ScaledClock running at normal speed on top of base.
+func NewScaledClock(base Clock) *ScaledClock {
+	now := base.Now()
+	return &ScaledClock{base: base, speed: 1, anchor: now, start: now}
+}
+
+// Now returns the current game time.
+func (c *ScaledClock) Now() time.Time {
+	return c.start.Add(c.sinceStart())
+}
+
+// sinceStart returns how much game time has passed since the clock was created.
+func (c *ScaledClock) sinceStart() time.Duration {
+	if c.paused {
+		return c.elapsed
+	}
+	return c.elapsed + time.Duration(float64(c.base.Now().Sub(c.anchor))*c.speed)
+}
+
+// rebase folds the game time passed so far into elapsed so speed or pause changes only apply from now on.
+func (c *ScaledClock) rebase() {
+	c.elapsed = c.sinceStart()
+	c.anchor = c.base.Now()
+}
+
+// SetSpeed changes how fast game time passes relative to the base clock.
+func (c *ScaledClock) SetSpeed(speed float64) {
+	c.rebase()
+	c.speed = speed
+}
+
+// Pause stops game time until Resume is called.
+func (c *ScaledClock) Pause() {
+	if c.paused {
+		return
+	}
+	c.rebase()
+	c.paused = true
+}
+
+// Resume restarts game time from where it was paused.
+func (c *ScaledClock) Resume() {
+	if !c.paused {
+		return
+	}
+	c.anchor = c.base.Now()
+	c.paused = false
+}
+
+// Paused reports whether game time is stopped.
+func (c *ScaledClock) Paused() bool {
+	return c.paused
+}
@@ -1,24 +1,115 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	typeGopher "gopher_typer"
 	"log"
-	"math/rand"
+	"os"
+	"time"
 )
 
 var (
-	r *rand.Rand
+	seed      = flag.Int64("seed", 0, "seed for the random number generator (0 picks one from the current time)")
+	record    = flag.String("record", "", "write a replay of the session to this file")
+	config    = flag.String("config", "", "read settings from this file (default $XDG_CONFIG_HOME/gopher_typer/config.json)")
+	save      = flag.String("save", "", "save and resume the game from this file (default $XDG_CONFIG_HOME/gopher_typer/save.json)")
+	metrics   = flag.String("metrics", "", "append this session's typing metrics as a line of JSON to this file")
+	level     = flag.String("difficulty", "", "difficulty preset to play: easy, normal, hard, custom or one from the config file")
+	wordPaths []string
 )
 
 func init() {
-	// Seed the random number generator with a fixed seed
-	r = rand.New(rand.NewSource(179876954235))
+	flag.Func("words", "load words from this file or directory instead of the built-in list (repeatable)", func(p string) error {
+		wordPaths = append(wordPaths, p)
+		return nil
+	})
 }
+
 func main() {
-	gt, err := typeGopher.NewGopherTyper()
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags]\n       %s replay <file>\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.Arg(0) == "replay" {
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		replay(flag.Arg(1))
+		return
+	}
+
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	opts := []typeGopher.Option{typeGopher.WithSeed(*seed)}
+	if len(wordPaths) > 0 {
+		opts = append(opts, typeGopher.WithWords(wordPaths...))
+	}
+	if *config != "" {
+		opts = append(opts, typeGopher.WithConfig(*config))
+	}
+	if *level != "" {
+		opts = append(opts, typeGopher.WithDifficulty(*level))
+	}
+	if *save != "" {
+		opts = append(opts, typeGopher.WithSaveFile(*save))
+	}
+	if *record != "" {
+		f, err := os.Create(*record)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		opts = append(opts, typeGopher.WithRecording(f))
+	}
+
+	gt, err := typeGopher.NewGopherTyper(opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := gt.Run(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Played with --seed=%d", *seed)
+
+	if *metrics != "" {
+		f, err := os.OpenFile(*metrics, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		if err := gt.Metrics().WriteJSON(f); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// replay plays back the session recorded in path.
+func replay(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rp, err := typeGopher.NewReplay(f)
+	f.Close()
 	if err != nil {
 		log.Fatal(err)
 	}
-	gt.Run()
 
+	// The replay only holds the seed, so it must be played with the words it was recorded with.
+	opts := []typeGopher.Option{typeGopher.WithReplay(rp)}
+	if len(wordPaths) > 0 {
+		opts = append(opts, typeGopher.WithWords(wordPaths...))
+	}
+	gt, err := typeGopher.NewGopherTyper(opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := gt.Run(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// serveAdmin serves pages for whoever runs the server on addr. It should
+// only be reachable by them, e.g. by listening on localhost.
+func serveAdmin(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sessions", handleSessions)
+	mux.HandleFunc("/metrics", handleMetrics)
+	slog.Info("Serving admin pages", "sessions", "http://"+addr+"/sessions", "metrics", "http://"+addr+"/metrics")
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		slog.Error("Admin pages stopped", "err", err)
+	}
+}
+
+// handleSessions lists who is playing or spectating: where from, for how
+// long and what they are up to.
+func handleSessions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	list := sessions.list()
+	games := len(sessions.games())
+	fmt.Fprintf(w, "%d playing, %d spectating\n\n", games, len(list)-games)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PLAYER\tADDRESS\tON FOR\tSCREEN\tLEVEL")
+	for _, s := range list {
+		screen, level := "spectating", "-"
+		if !s.spectating {
+			st := s.game.Status()
+			screen, level = st.Screen, strconv.Itoa(st.Level)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			s.player(),
+			s.conn.RemoteAddr(),
+			time.Since(s.started).Round(time.Second),
+			screen,
+			level)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Keys of the permission extensions that carry a player's identity from
+// authentication to their session.
+const (
+	extPlayer      = "player"
+	extFingerprint = "fingerprint"
+)
+
+// loadAuthorizedKeys reads a file in the authorized_keys format, mapping each
+// key to the name of the player it belongs to, which is taken from the key's
+// comment. Every key must have one, since high scores are kept by name and
+// the SSH username is whatever the client says it is.
+func loadAuthorizedKeys(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	keys := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+		key, comment, _, _, err := ssh.ParseAuthorizedKey(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if comment == "" {
+			return nil, fmt.Errorf("%s:%d: key has no comment naming its player", path, line)
+		}
+		keys[string(key.Marshal())] = comment
+	}
+	return keys, scanner.Err()
+}
+
+// newServerConfig lets players with a key in keys sign in as themselves and,
+// if guests is set, lets anyone else in under an anonymous guest name.
+func newServerConfig(keys map[string]string, guests bool) *ssh.ServerConfig {
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			name, ok := keys[string(key.Marshal())]
+			if !ok {
+				return nil, errors.New("unknown public key")
+			}
+			return &ssh.Permissions{Extensions: map[string]string{
+				extPlayer:      name,
+				extFingerprint: ssh.FingerprintSHA256(key),
+			}}, nil
+		},
+	}
+	if guests {
+		// Clients try keyboard-interactive after their keys, and it needs no
+		// questions, so players without an authorized key still get in.
+		config.KeyboardInteractiveCallback = func(conn ssh.ConnMetadata, client ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			return &ssh.Permissions{Extensions: map[string]string{extPlayer: guestName()}}, nil
+		}
+	}
+	return config
+}
+
+// guestName makes up a name for a player who hasn't signed in with a key.
+func guestName() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return "guest-" + hex.EncodeToString(b)
+}
+
+// savePath returns where the game of the player with the given key
+// fingerprint is saved, within dir.
+func savePath(dir, fingerprint string) string {
+	name := strings.NewReplacer("/", "_", "+", "-").Replace(strings.TrimPrefix(fingerprint, "SHA256:"))
+	return filepath.Join(dir, name+".json")
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Every flag can also be set with an environment variable of the same name,
+// upper-cased and prefixed with envPrefix, e.g. GOPHER_TYPER_LISTEN for --listen.
+const envPrefix = "GOPHER_TYPER_"
+
+// envName returns the environment variable that sets the flag with the given name.
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// envString returns the value of the flag's environment variable, or def if it isn't set.
+func envString(flagName, def string) string {
+	if v, ok := os.LookupEnv(envName(flagName)); ok {
+		return v
+	}
+	return def
+}
+
+// envBool returns the value of the flag's environment variable as a bool, or def if it isn't set.
+func envBool(flagName string, def bool) bool {
+	v, ok := os.LookupEnv(envName(flagName))
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatalf("Invalid %s (%s)", envName(flagName), err)
+	}
+	return b
+}
+
+// envInt returns the value of the flag's environment variable as an int, or def if it isn't set.
+func envInt(flagName string, def int) int {
+	v, ok := os.LookupEnv(envName(flagName))
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("Invalid %s (%s)", envName(flagName), err)
+	}
+	return n
+}
+
+// envDuration returns the value of the flag's environment variable as a duration, or def if it isn't set.
+func envDuration(flagName string, def time.Duration) time.Duration {
+	v, ok := os.LookupEnv(envName(flagName))
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("Invalid %s (%s)", envName(flagName), err)
+	}
+	return d
+}
+
+// envList returns the value of the flag's environment variable split at commas, or nil if it isn't set.
+func envList(flagName string) []string {
+	v := envString(flagName, "")
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
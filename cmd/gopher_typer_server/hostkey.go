@@ -0,0 +1,90 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	typeGopher "gopher_typer"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// legacyHostKeyPath is where older versions of the server read their only host key from.
+const legacyHostKeyPath = "id_rsa"
+
+// defaultHostKeyPaths returns where host keys are kept when none are given:
+// one ed25519 and one RSA key, for clients that don't support ed25519. A
+// server upgraded from an older version keeps the key it already has, so
+// returning players aren't told the host has changed.
+func defaultHostKeyPaths() ([]string, error) {
+	if _, err := os.Stat(legacyHostKeyPath); err == nil {
+		slog.Info("Using the host key in the working directory", "path", legacyHostKeyPath)
+		return []string{legacyHostKeyPath}, nil
+	}
+	dir, err := typeGopher.ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		filepath.Join(dir, "ssh_host_ed25519_key"),
+		filepath.Join(dir, "ssh_host_rsa_key"),
+	}, nil
+}
+
+// loadHostKey reads the host key at path. If there is none and generate is
+// set, a new key is made and written there first. Its type is taken from
+// the file name: RSA if it mentions "rsa", ECDSA if it mentions "ecdsa" and
+// ed25519 otherwise.
+func loadHostKey(path string, generate bool) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && generate {
+		if data, err = generateHostKey(path); err == nil {
+			slog.Info("Generated new host key", "path", path)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ssh.ParsePrivateKey(data)
+}
+
+// generateHostKey makes a new host key of the type named by path and writes it there.
+func generateHostKey(path string) ([]byte, error) {
+	var key crypto.PrivateKey
+	var err error
+	switch name := filepath.Base(path); {
+	case strings.Contains(name, "ecdsa"):
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case strings.Contains(name, "rsa"):
+		key, err = rsa.GenerateKey(rand.Reader, 3072)
+	default:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
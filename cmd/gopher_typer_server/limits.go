@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ipLimiter caps how many connections each IP address can have open at once.
+type ipLimiter struct {
+	mu    sync.Mutex
+	conns map[string]int
+	// max is the most connections an address may have, or 0 for no limit.
+	max int
+}
+
+// connsPerIP counts the connections open from each address.
+var connsPerIP = &ipLimiter{conns: map[string]int{}}
+
+// acquire counts a new connection from addr, unless it already has as many as it may.
+func (l *ipLimiter) acquire(addr net.Addr) bool {
+	ip := hostOf(addr)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.max > 0 && l.conns[ip] >= l.max {
+		return false
+	}
+	l.conns[ip]++
+	return true
+}
+
+// release stops counting a connection from addr once it has closed.
+func (l *ipLimiter) release(addr net.Addr) {
+	ip := hostOf(addr)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conns[ip]--; l.conns[ip] <= 0 {
+		delete(l.conns, ip)
+	}
+}
+
+// hostOf returns the IP address of addr without its port.
+func hostOf(addr net.Addr) string {
+	if tcp, ok := addr.(*net.TCPAddr); ok {
+		return tcp.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// idleClock remembers when a connection last had any input from its client,
+// on any of its channels.
+type idleClock struct {
+	// last is when input last arrived, in Unix nanoseconds.
+	last atomic.Int64
+}
+
+// newIdleClock returns a clock that counts the connection as active from now.
+func newIdleClock() *idleClock {
+	c := &idleClock{}
+	c.touch()
+	return c
+}
+
+// touch notes that input has just arrived.
+func (c *idleClock) touch() {
+	c.last.Store(time.Now().UnixNano())
+}
+
+// idleFor returns how long it has been since input last arrived.
+func (c *idleClock) idleFor() time.Duration {
+	return time.Since(time.Unix(0, c.last.Load()))
+}
+
+// activeChannel is an SSH channel that keeps its connection's idleClock up
+// to date with what the player types.
+type activeChannel struct {
+	ssh.Channel
+	idle *idleClock
+	// eof is set once the client has closed its side of the channel.
+	eof atomic.Bool
+}
+
+// newActiveChannel wraps ch, touching idle whenever input arrives on it.
+func newActiveChannel(ch ssh.Channel, idle *idleClock) *activeChannel {
+	return &activeChannel{Channel: ch, idle: idle}
+}
+
+// Read reads the player's input, noting that they are still there.
+func (c *activeChannel) Read(p []byte) (int, error) {
+	n, err := c.Channel.Read(p)
+	if n > 0 {
+		c.idle.touch()
+	}
+	if err != nil {
+		c.eof.Store(true)
+	}
+	return n, err
+}
@@ -1,168 +1,191 @@
 package main
 
 import (
-	"encoding/binary"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
+	typeGopher "gopher_typer"
 	"log"
+	"log/slog"
 	"net"
 	"os"
-	"os/exec"
-	"sync"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 
-	"github.com/creack/pty"
 	"golang.org/x/crypto/ssh"
 )
 
-// Winsize stores terminal dimensions
-type Winsize struct {
-	Cols uint16
-	Rows uint16
+var (
+	// lobby holds the race rooms shared by every session on the server.
+	lobby = typeGopher.NewLobby()
+	// scores is the high score table shared by every session on the server.
+	scores *typeGopher.ScoreBoard
+	// savesDir holds the saved games of players who sign in with a key.
+	savesDir string
+
+	listen           = flag.String("listen", envString("listen", "0.0.0.0:2200"), "address to listen for SSH connections on")
+	generateHostKeys = flag.Bool("generate-host-keys", envBool("generate-host-keys", true), "generate any host key that doesn't exist yet")
+	maxSessions      = flag.Int("max-sessions", envInt("max-sessions", 100), "most players and spectators that can be on at once, or 0 for no limit")
+	authorizedKeys   = flag.String("authorized-keys", envString("authorized-keys", ""), "let players whose public key is in this file sign in as themselves, named by the key's comment")
+	guests           = flag.Bool("guests", envBool("guests", true), "let players without an authorized key in under an anonymous guest name")
+	shutdownTimeout  = flag.Duration("shutdown-timeout", envDuration("shutdown-timeout", 30*time.Second), "how long players get to finish their game when the server is stopped")
+	adminListen      = flag.String("admin-listen", envString("admin-listen", ""), "serve a page listing active sessions over HTTP on this address, e.g. 127.0.0.1:2201")
+	idleTimeout      = flag.Duration("idle-timeout", envDuration("idle-timeout", 10*time.Minute), "disconnect players who press no keys for this long, or 0 to never")
+	maxPerIP         = flag.Int("max-per-ip", envInt("max-per-ip", 4), "most connections one IP address can have open at once, or 0 for no limit")
+	handshakeTimeout = flag.Duration("handshake-timeout", envDuration("handshake-timeout", 10*time.Second), "drop connections that haven't signed in within this long")
+	logFormat        = flag.String("log-format", envString("log-format", "text"), "write logs as key=value \"text\" or \"json\"")
+	hostKeyPaths     []string
+)
+
+func init() {
+	flag.Func("host-key", "load a host key from this file (repeatable, default ed25519 and RSA keys in $XDG_CONFIG_HOME/gopher_typer)", func(p string) error {
+		hostKeyPaths = append(hostKeyPaths, p)
+		return nil
+	})
 }
 
 // main Sets up an SSH server, loads the private key, listens for connections, and processes them.
 func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags]\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintf(flag.CommandLine.Output(), "\nEvery flag can also be set in the environment, e.g. %s=:22 for --listen.\n"+
+			"Separate several host keys in %s with commas.\n", envName("listen"), envName("host-key"))
+	}
+	flag.Parse()
+	switch *logFormat {
+	case "text":
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	case "json":
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+	default:
+		log.Fatalf("Unknown log format %q", *logFormat)
+	}
+	if len(hostKeyPaths) == 0 {
+		hostKeyPaths = envList("host-key")
+	}
+	sessions.max = *maxSessions
+	connsPerIP.max = *maxPerIP
+
+	keys := map[string]string{}
+	if *authorizedKeys != "" {
+		var err error
+		if keys, err = loadAuthorizedKeys(*authorizedKeys); err != nil {
+			log.Fatalf("Failed to load authorized keys (%s)", err)
+		}
+	}
+	if len(keys) == 0 && !*guests {
+		log.Fatal("No authorized keys and guests are not allowed, so nobody could play")
+	}
 
-	// Configure server and load private key
-	// You can generate a keypair with 'ssh-keygen -t rsa'
-	config := &ssh.ServerConfig{NoClientAuth: true}
-	privateBytes, err := os.ReadFile("id_rsa")
-	if err != nil {
-		log.Fatal("Failed to load private key (./id_rsa)")
+	// Configure server and load its host keys, making them on first start
+	config := newServerConfig(keys, *guests)
+	if len(hostKeyPaths) == 0 {
+		var err error
+		if hostKeyPaths, err = defaultHostKeyPaths(); err != nil {
+			log.Fatalf("Failed to find host keys (%s)", err)
+		}
 	}
-	private, err := ssh.ParsePrivateKey(privateBytes)
+	for _, path := range hostKeyPaths {
+		key, err := loadHostKey(path, *generateHostKeys)
+		if err != nil {
+			log.Fatalf("Failed to load host key %s (%s)", path, err)
+		}
+		config.AddHostKey(key)
+	}
+
+	scoresPath, err := typeGopher.DefaultScoresPath()
 	if err != nil {
-		log.Fatal("Failed to parse private key")
+		log.Fatalf("Failed to find the high score table (%s)", err)
 	}
-	config.AddHostKey(private)
+	if scores, err = typeGopher.OpenScoreBoard(scoresPath); err != nil {
+		log.Fatalf("Failed to load the high score table (%s)", err)
+	}
+	savesDir = filepath.Join(filepath.Dir(scoresPath), "saves")
 
 	// Start listening for connections
-	listener, err := net.Listen("tcp", "0.0.0.0:2200")
+	listener, err := net.Listen("tcp", *listen)
 	if err != nil {
-		log.Fatalf("Failed to listen on 2200 (%s)", err)
+		log.Fatalf("Failed to listen on %s (%s)", *listen, err)
+	}
+	slog.Info("Listening", "addr", listener.Addr().String())
+
+	if *adminListen != "" {
+		go serveAdmin(*adminListen)
 	}
-	log.Print("Listening on 2200...")
+
+	// On SIGINT or SIGTERM stop taking new players and give those playing time to finish
+	drained := make(chan struct{})
+	go func() {
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+		sig := <-sigs
+		slog.Info("Shutting down", "signal", sig.String(), "timeout", shutdownTimeout.String())
+		listener.Close()
+		sessions.shutdown(*shutdownTimeout)
+		close(drained)
+	}()
 
 	// Accept and handle connections
 	for {
 		tcpConn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			break
+		}
 		if err != nil {
-			log.Printf("Failed to accept incoming connection (%s)", err)
+			slog.Warn("Failed to accept incoming connection", "err", err)
 			continue
 		}
-		// Before use, a handshake must be performed on the incoming net.Conn.
-		sshConn, chans, reqs, err := ssh.NewServerConn(tcpConn, config)
-		if err != nil {
-			log.Printf("Failed to handshake (%s)", err)
+		if !connsPerIP.acquire(tcpConn.RemoteAddr()) {
+			slog.Info("Turned away", "remote", tcpConn.RemoteAddr().String(), "reason", "too many connections from this address")
+			tcpConn.Close()
 			continue
 		}
-
-		log.Printf("New SSH connection from %s (%s)", sshConn.RemoteAddr(), sshConn.ClientVersion())
-		// Discard all global out-of-band Requests
-		go ssh.DiscardRequests(reqs)
-		// Accept all channels
-		go handleChannels(chans)
+		go handleConn(tcpConn, config)
 	}
+	<-drained
+	slog.Info("Shut down")
 }
 
-// handleChannels Services incoming SSH channels in a separate goroutine.
-func handleChannels(chans <-chan ssh.NewChannel) {
-	// Service the incoming Channel channel in go routine
-	for newChannel := range chans {
-		go handleChannel(newChannel)
-	}
-}
+// handleConn performs the SSH handshake on a new connection and then serves it until it closes.
+func handleConn(tcpConn net.Conn, config *ssh.ServerConfig) {
+	defer connsPerIP.release(tcpConn.RemoteAddr())
 
-// handleChannel Handles a single SSH channel, starts a shell session, and processes requests.
-func handleChannel(newChannel ssh.NewChannel) {
-	//  Expect a channel type of "session" with a shell
-	if t := newChannel.ChannelType(); t != "session" {
-		newChannel.Reject(ssh.UnknownChannelType, fmt.Sprintf("unknown channel type: %s", t))
-		return
+	// Before use, a handshake must be performed on the incoming net.Conn.
+	// Clients that stall partway through it are dropped.
+	if *handshakeTimeout > 0 {
+		tcpConn.SetDeadline(time.Now().Add(*handshakeTimeout))
 	}
-
-	// Accept or reject client connection
-	connection, requests, err := newChannel.Accept()
+	sshConn, chans, reqs, err := ssh.NewServerConn(tcpConn, config)
 	if err != nil {
-		log.Printf("Could not accept channel (%s)", err)
+		counters.handshakeFailures.Add(1)
+		slog.Info("Failed to handshake", "remote", tcpConn.RemoteAddr().String(), "err", err)
+		tcpConn.Close()
 		return
 	}
-
-	// Fire up bash for this session
-	bash := exec.Command("./main")
-
-	// Prepare teardown function
-	c := func() {
-		connection.Close()
-		_, err := bash.Process.Wait()
-		if err != nil {
-			log.Printf("Failed to exit bash (%s)", err)
-		}
-		log.Printf("Session closed")
+	tcpConn.SetDeadline(time.Time{})
+
+	slog.Info("New SSH connection", "remote", sshConn.RemoteAddr().String(), "client", string(sshConn.ClientVersion()),
+		"player", sshConn.Permissions.Extensions[extPlayer], "user", sshConn.User())
+	// Discard all global out-of-band Requests
+	go ssh.DiscardRequests(reqs)
+	// Accept all channels, timing how long the client goes without typing on any of them
+	idle := newIdleClock()
+	go handleChannels(sshConn, chans, idle)
+	if *idleTimeout > 0 {
+		closed := make(chan struct{})
+		defer close(closed)
+		go watchIdle(sshConn, idle, *idleTimeout, closed)
 	}
-
-	// Allocate a terminal for this channel
-	log.Print("Creating pty...")
-	bashf, err := pty.Start(bash)
-	if err != nil {
-		log.Printf("Could not start pty (%s)", err)
-		c()
-		return
-	}
-
-	// Pipe session connection to bash and bash to connection
-	var once sync.Once
-	go func() {
-		io.Copy(connection, bashf)
-		once.Do(c)
-	}()
-	go func() {
-		io.Copy(bashf, connection)
-		once.Do(c)
-	}()
-
-	// Sessions have out-of-band requests such as "shell", "pty-req" and "env"
-	// Provides a conceptually independent channel that allows for any data to be sent
-	go func() {
-		for req := range requests {
-			switch req.Type {
-			case "shell":
-				// We only accept the default shell
-				// (i.e. no command in the Payload)
-				if len(req.Payload) == 0 {
-					req.Reply(true, nil)
-				}
-			case "pty-req":
-				termLen := req.Payload[3]
-				w, h := parseDims(req.Payload[termLen+4:])
-				SetWinsize(bashf.Fd(), w, h)
-				// Responding true (OK) here will let the client
-				// know we have a pty ready for input
-				req.Reply(true, nil)
-			case "window-change":
-				w, h := parseDims(req.Payload)
-				SetWinsize(bashf.Fd(), w, h)
-			}
-		}
-	}()
+	sshConn.Wait()
 }
 
-// parseDims extracts terminal dimensions (width x height) from the provided buffer.
-func parseDims(b []byte) (uint32, uint32) {
-	w := binary.BigEndian.Uint32(b)
-	h := binary.BigEndian.Uint32(b[4:])
-	return w, h
-}
-
-// SetWinsize sets the size of the given pseudotty.
-func SetWinsize(fd uintptr, cols, rows uint32) error {
-	ws := &Winsize{
-		Cols: uint16(cols),
-		Rows: uint16(rows),
-	}
-	ptyWs := &pty.Winsize{
-		Cols: ws.Cols,
-		Rows: ws.Rows,
+// handleChannels Services incoming SSH channels in a separate goroutine.
+func handleChannels(sshConn *ssh.ServerConn, chans <-chan ssh.NewChannel, idle *idleClock) {
+	// Service the incoming Channel channel in go routine
+	for newChannel := range chans {
+		go handleChannel(sshConn, newChannel, idle)
 	}
-	return pty.Setsize(os.NewFile(fd, "ptyFd"), ptyWs)
 }
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// counters are the server's running totals, served in Prometheus' text format by handleMetrics.
+var counters struct {
+	games             atomic.Int64
+	handshakeFailures atomic.Int64
+	// levelsCompleted counts the levels won in games that have ended; see
+	// sessionList.levelsCompleted for the total including those still being played.
+	levelsCompleted atomic.Int64
+}
+
+// handleMetrics serves the server's counters for Prometheus to scrape.
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	list := sessions.list()
+	games := len(sessions.games())
+	levels := sessions.levelsCompleted()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	metric := func(name, kind, help string, value int64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", name, help, name, kind, name, value)
+	}
+	metric("gopher_typer_active_sessions", "gauge", "Games being played right now.", int64(games))
+	metric("gopher_typer_spectators", "gauge", "Spectators watching right now.", int64(len(list)-games))
+	metric("gopher_typer_games_total", "counter", "Games started since the server started.", counters.games.Load())
+	metric("gopher_typer_handshake_failures_total", "counter", "Connections dropped before they signed in.", counters.handshakeFailures.Load())
+	metric("gopher_typer_levels_completed_total", "counter", "Levels won by every player.", levels)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// The payloads of the session requests we handle, as laid out in RFC 4254.
+type (
+	ptyRequest struct {
+		Term          string
+		Columns, Rows uint32
+		Width, Height uint32
+		Modes         string
+	}
+	windowChange struct {
+		Columns, Rows uint32
+		Width, Height uint32
+	}
+	envRequest struct {
+		Name, Value string
+	}
+	execRequest struct {
+		Command string
+	}
+)
+
+// The largest terminal a player can ask for, so a bogus size can't make the
+// game allocate an enormous screen.
+const (
+	maxColumns = 1000
+	maxRows    = 500
+)
+
+// dims returns the terminal size the client asked for. It isn't ok if the
+// client gave only a size in pixels, which the game has no use for.
+func dims(columns, rows uint32) (w, h int, ok bool) {
+	if columns == 0 || rows == 0 {
+		return 0, 0, false
+	}
+	if columns > maxColumns {
+		columns = maxColumns
+	}
+	if rows > maxRows {
+		rows = maxRows
+	}
+	return int(columns), int(rows), true
+}
+
+// parseModes decodes the terminal modes of a pty-req: opcode and uint32
+// argument pairs, ending at TTY_OP_END. Parsing stops at the first opcode
+// above 159, which have no defined argument.
+func parseModes(b string) (ssh.TerminalModes, error) {
+	modes := ssh.TerminalModes{}
+	for len(b) > 0 {
+		op := b[0]
+		if op == 0 || op >= 160 {
+			return modes, nil
+		}
+		if len(b) < 5 {
+			return nil, errors.New("truncated terminal modes")
+		}
+		modes[op] = binary.BigEndian.Uint32([]byte(b[1:5]))
+		b = b[5:]
+	}
+	return modes, nil
+}
+
+// acceptEnv reports whether an env request may set the named variable. As
+// with OpenSSH's defaults, only the locale and terminal are let through.
+func acceptEnv(name string) bool {
+	return name == "LANG" || name == "TERM" || name == "COLORTERM" || strings.HasPrefix(name, "LC_")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestDims(t *testing.T) {
+	tests := []struct {
+		name          string
+		columns, rows uint32
+		wantW, wantH  int
+		wantOK        bool
+	}{
+		{"usual size", 80, 24, 80, 24, true},
+		{"smallest size", 1, 1, 1, 1, true},
+		{"largest size", maxColumns, maxRows, maxColumns, maxRows, true},
+		{"no columns", 0, 24, 0, 0, false},
+		{"no rows", 80, 0, 0, 0, false},
+		{"pixels only", 0, 0, 0, 0, false},
+		{"too wide", 5000, 24, maxColumns, 24, true},
+		{"too tall", 80, 9000, 80, maxRows, true},
+		{"far too big", math.MaxUint32, math.MaxUint32, maxColumns, maxRows, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h, ok := dims(tt.columns, tt.rows)
+			if w != tt.wantW || h != tt.wantH || ok != tt.wantOK {
+				t.Errorf("dims(%d, %d) = %d, %d, %v, want %d, %d, %v", tt.columns, tt.rows, w, h, ok, tt.wantW, tt.wantH, tt.wantOK)
+			}
+		})
+	}
+}
+
+// mode encodes a terminal mode as it appears in a pty-req.
+func mode(op byte, arg uint32) string {
+	b := []byte{op, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(b[1:], arg)
+	return string(b)
+}
+
+func TestParseModes(t *testing.T) {
+	const end = "\x00"
+	tests := []struct {
+		name    string
+		modes   string
+		want    ssh.TerminalModes
+		wantErr bool
+	}{
+		{"no modes", "", ssh.TerminalModes{}, false},
+		{"only the end", end, ssh.TerminalModes{}, false},
+		{"one mode", mode(ssh.ECHO, 1) + end, ssh.TerminalModes{ssh.ECHO: 1}, false},
+		{"several modes", mode(ssh.ECHO, 0) + mode(ssh.TTY_OP_ISPEED, 38400) + end,
+			ssh.TerminalModes{ssh.ECHO: 0, ssh.TTY_OP_ISPEED: 38400}, false},
+		{"no end", mode(ssh.ECHO, 1), ssh.TerminalModes{ssh.ECHO: 1}, false},
+		{"nothing read after the end", mode(ssh.ECHO, 1) + end + mode(ssh.ICANON, 1), ssh.TerminalModes{ssh.ECHO: 1}, false},
+		{"undefined opcode stops parsing", mode(ssh.ECHO, 1) + "\xa0\x01", ssh.TerminalModes{ssh.ECHO: 1}, false},
+		{"opcode without argument", string([]byte{ssh.ECHO}), nil, true},
+		{"truncated argument", mode(ssh.ECHO, 1)[:3], nil, true},
+		{"truncated after a mode", mode(ssh.ECHO, 1) + mode(ssh.ICANON, 1)[:4], nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseModes(tt.modes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseModes() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseModes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,517 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	typeGopher "gopher_typer"
+	"gopher_typer/term"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// session is a single SSH channel with a game running on it, or a spectator
+// watching other sessions' games.
+type session struct {
+	id       uint64
+	log      *slog.Logger
+	conn     *ssh.ServerConn
+	channel  *activeChannel
+	terminal *term.ANSI
+	game     *typeGopher.GopherTyper
+	// started is when the game began, or the spectator started watching.
+	started time.Time
+	// done is closed once the game has finished or the spectator has left.
+	done chan struct{}
+	// spectating is set if the session watches games rather than playing one.
+	// A spectator has no game, so noticed tells it that notice has changed,
+	// and quit is closed to make it leave.
+	spectating bool
+	noticed    chan struct{}
+	quit       chan struct{}
+	quitOnce   sync.Once
+
+	// env holds the variables the client set, including TERM from its pty-req.
+	env map[string]string
+	// pty is set once the client has asked for a terminal, and modes holds
+	// the terminal modes it asked for, which the game leaves alone.
+	pty   bool
+	modes ssh.TerminalModes
+	// running is set once a shell or command has been started; a session runs only one.
+	running bool
+
+	// reason says why the game ended, for the log, and goodbye, if set,
+	// tells the player. notice is the message shown to a spectator.
+	mu      sync.Mutex
+	reason  string
+	goodbye string
+	notice  string
+}
+
+// nextSessionID numbers sessions so their log lines can be told apart.
+var nextSessionID atomic.Uint64
+
+// sessionList keeps track of the games being played on the server and the
+// spectators watching them.
+type sessionList struct {
+	mu       sync.Mutex
+	sessions map[*session]bool
+	// max is the most sessions that may be on at once, playing or
+	// spectating, or 0 for no limit.
+	max int
+	// closing is set once the server has begun shutting down.
+	closing bool
+}
+
+// sessions are the games being played on the server and their spectators.
+var sessions = &sessionList{sessions: map[*session]bool{}}
+
+var (
+	errServerFull   = errors.New("the server is full, please try again later")
+	errShuttingDown = errors.New("the server is shutting down, please try again later")
+)
+
+// add counts s as on the server, unless it is full or shutting down.
+func (l *sessionList) add(s *session) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closing {
+		return errShuttingDown
+	}
+	if l.max > 0 && len(l.sessions) >= l.max {
+		return errServerFull
+	}
+	l.sessions[s] = true
+	return nil
+}
+
+// remove stops counting s once its game is over or the spectator has left,
+// adding the levels it won to the server's total.
+func (l *sessionList) remove(s *session) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.sessions, s)
+	if !s.spectating {
+		counters.levelsCompleted.Add(int64(s.game.Status().Won))
+	}
+}
+
+// levelsCompleted returns how many levels have been won on the server, in
+// games that have ended and those still being played.
+func (l *sessionList) levelsCompleted() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := counters.levelsCompleted.Load()
+	for s := range l.sessions {
+		if !s.spectating {
+			n += int64(s.game.Status().Won)
+		}
+	}
+	return n
+}
+
+// list returns the sessions on the server right now, playing or spectating,
+// longest on first.
+func (l *sessionList) list() []*session {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	list := make([]*session, 0, len(l.sessions))
+	for s := range l.sessions {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].started.Before(list[j].started) })
+	return list
+}
+
+// games returns the sessions playing right now, longest playing first.
+func (l *sessionList) games() []*session {
+	var games []*session
+	for _, s := range l.list() {
+		if !s.spectating {
+			games = append(games, s)
+		}
+	}
+	return games
+}
+
+// on returns the sessions on the server over conn.
+func (l *sessionList) on(conn *ssh.ServerConn) []*session {
+	var on []*session
+	for _, s := range l.list() {
+		if s.conn == conn {
+			on = append(on, s)
+		}
+	}
+	return on
+}
+
+// shutdown turns away new players and spectators and counts down on the
+// screens of those on the server, giving players until timeout to finish.
+// Games still running and spectators still watching after that are stopped. It returns once every game has ended, or once it has
+// given up waiting for them.
+func (l *sessionList) shutdown(timeout time.Duration) {
+	l.mu.Lock()
+	l.closing = true
+	l.mu.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		list := l.list()
+		if len(list) == 0 {
+			return
+		}
+		left := time.Until(deadline).Round(time.Second)
+		if left <= 0 {
+			slog.Info("Stopping unfinished games", "games", len(list))
+			var wg sync.WaitGroup
+			for _, s := range list {
+				wg.Add(1)
+				go func(s *session) {
+					defer wg.Done()
+					s.kick("shutdown", "The server has shut down.")
+					<-s.done
+				}(s)
+			}
+			stopped := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-time.After(2 * kickGrace):
+				slog.Warn("Gave up waiting for games to end", "games", len(l.list()))
+			}
+			return
+		}
+		for _, s := range list {
+			s.notify(fmt.Sprintf("The server is shutting down in %s", left))
+		}
+		<-tick.C
+	}
+}
+
+// handleChannel Handles a single SSH channel, running a game on it once the
+// client asks for a shell. Input on the channel keeps the connection's idle clock going.
+func handleChannel(sshConn *ssh.ServerConn, newChannel ssh.NewChannel, idle *idleClock) {
+	//  Expect a channel type of "session" with a shell
+	if t := newChannel.ChannelType(); t != "session" {
+		newChannel.Reject(ssh.UnknownChannelType, fmt.Sprintf("unknown channel type: %s", t))
+		return
+	}
+
+	// Accept or reject client connection
+	connection, requests, err := newChannel.Accept()
+	if err != nil {
+		slog.Warn("Could not accept channel", "remote", sshConn.RemoteAddr(), "err", err)
+		return
+	}
+
+	// The game draws straight onto the channel, at the size given by pty-req
+	channel := newActiveChannel(connection, idle)
+	id := nextSessionID.Add(1)
+	s := &session{
+		id:       id,
+		log:      slog.With("session", id, "remote", sshConn.RemoteAddr().String(), "player", sshConn.Permissions.Extensions[extPlayer]),
+		conn:     sshConn,
+		channel:  channel,
+		terminal: term.NewANSI(channel, 80, 24),
+		done:     make(chan struct{}),
+		noticed:  make(chan struct{}, 1),
+		quit:     make(chan struct{}),
+		env:      map[string]string{},
+	}
+	defer s.close()
+
+	// Sessions have out-of-band requests such as "shell", "pty-req" and "env".
+	// The channel is closed when the game ends, which ends this loop too.
+	for req := range requests {
+		s.handleRequest(req)
+	}
+}
+
+// handleRequest answers a single out-of-band request on the session's
+// channel, replying to every request that wants a reply.
+func (s *session) handleRequest(req *ssh.Request) {
+	switch req.Type {
+	case "pty-req":
+		var pty ptyRequest
+		if err := ssh.Unmarshal(req.Payload, &pty); err != nil {
+			req.Reply(false, nil)
+			return
+		}
+		modes, err := parseModes(pty.Modes)
+		if err != nil {
+			req.Reply(false, nil)
+			return
+		}
+		s.pty, s.modes = true, modes
+		s.env["TERM"] = pty.Term
+		if w, h, ok := dims(pty.Columns, pty.Rows); ok {
+			s.terminal.Resize(w, h)
+		}
+		// Responding true (OK) here will let the client
+		// know we have a pty ready for input
+		req.Reply(true, nil)
+	case "window-change":
+		var wc windowChange
+		if err := ssh.Unmarshal(req.Payload, &wc); err != nil {
+			req.Reply(false, nil)
+			return
+		}
+		if w, h, ok := dims(wc.Columns, wc.Rows); ok {
+			s.terminal.Resize(w, h)
+		}
+		req.Reply(true, nil)
+	case "env":
+		var env envRequest
+		ok := ssh.Unmarshal(req.Payload, &env) == nil && acceptEnv(env.Name)
+		if ok {
+			s.env[env.Name] = env.Value
+		}
+		req.Reply(ok, nil)
+	case "shell":
+		// A shell request has no payload
+		ok := len(req.Payload) == 0 && !s.running
+		req.Reply(ok, nil)
+		if !ok {
+			return
+		}
+		s.running = true
+		if s.conn.User() == spectateUser {
+			s.spectating = true
+			go s.spectate()
+		} else {
+			s.start()
+		}
+	case "exec":
+		var exec execRequest
+		ok := ssh.Unmarshal(req.Payload, &exec) == nil && !s.running
+		req.Reply(ok, nil)
+		if ok {
+			s.running = true
+			s.exec(exec.Command)
+		}
+	default:
+		req.Reply(false, nil)
+	}
+}
+
+// exec runs a command instead of the game, such as "ssh host scores", and closes the channel.
+func (s *session) exec(command string) {
+	s.log.Info("Ran command", "command", command)
+	var out bytes.Buffer
+	status := 0
+	switch strings.TrimSpace(command) {
+	case "scores":
+		scores.WriteTable(&out)
+	default:
+		fmt.Fprintf(s.channel.Stderr(), "Unknown command %q. Run \"scores\" for the high score table, or nothing to play.\r\n", command)
+		status = 127
+	}
+	// With a pty the client's terminal is raw, so lines need a carriage return too
+	b := out.Bytes()
+	if s.pty {
+		b = bytes.ReplaceAll(b, []byte("\n"), []byte("\r\n"))
+	}
+	s.channel.Write(b)
+	s.exit(status)
+}
+
+// start runs a game on the channel, closing the channel once the player quits.
+func (s *session) start() {
+	opts := []typeGopher.Option{
+		typeGopher.WithTerminal(s.terminal),
+		typeGopher.WithLobby(lobby),
+		typeGopher.WithScoreBoard(scores),
+		typeGopher.WithPlayer(s.player()),
+	}
+	// Only players who signed in with a key can come back to their game; guests have nowhere to save.
+	if fp := s.conn.Permissions.Extensions[extFingerprint]; fp != "" {
+		opts = append(opts, typeGopher.WithSaveFile(savePath(savesDir, fp)))
+	} else {
+		opts = append(opts, typeGopher.WithoutSaving())
+	}
+	gt, err := typeGopher.NewGopherTyper(opts...)
+	if err != nil {
+		s.log.Error("Could not start game", "err", err)
+		fmt.Fprintf(s.channel, "Could not start game: %s\r\n", err)
+		s.exit(1)
+		close(s.done)
+		return
+	}
+	s.game = gt
+	s.started = time.Now()
+
+	if err := sessions.add(s); err != nil {
+		s.log.Info("Turned away", "reason", err)
+		fmt.Fprintf(s.channel, "Sorry, %s.\r\n", err)
+		s.exit(1)
+		s.game = nil
+		close(s.done)
+		return
+	}
+
+	counters.games.Add(1)
+	s.log.Info("Game started", "term", s.env["TERM"])
+
+	go func() {
+		defer close(s.done)
+		defer sessions.remove(s)
+		status := 0
+		err := gt.Run()
+		s.mu.Lock()
+		if err != nil {
+			status = 1
+		}
+		switch {
+		case s.reason != "":
+		case err != nil:
+			s.reason = "error"
+		case s.channel.eof.Load():
+			s.reason = "disconnected"
+		default:
+			s.reason = "quit"
+		}
+		if s.goodbye != "" {
+			fmt.Fprintf(s.channel, "%s\r\n", s.goodbye)
+		}
+		reason := s.reason
+		s.mu.Unlock()
+
+		st := gt.Status()
+		attrs := []any{"reason", reason, "level", st.Level, "won", st.Won, "duration", time.Since(s.started).Round(time.Second).String()}
+		if err != nil {
+			attrs = append(attrs, "err", err)
+		}
+		s.log.Info("Game ended", attrs...)
+		s.exit(status)
+	}()
+}
+
+// idleWarning is how long before disconnecting an idle player they are warned.
+const idleWarning = time.Minute
+
+// watchIdle warns the players on conn once nothing has been typed on it for a
+// while, and disconnects it if nothing is typed for timeout, until closed is
+// closed. Connections that never start a game are disconnected all the same,
+// so they can't hold on to a place under --max-per-ip.
+func watchIdle(conn *ssh.ServerConn, idle *idleClock, timeout time.Duration, closed <-chan struct{}) {
+	warn := idleWarning
+	if warn > timeout/2 {
+		warn = timeout / 2
+	}
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	warned := false
+	for {
+		select {
+		case <-closed:
+			return
+		case <-tick.C:
+		}
+		left := timeout - idle.idleFor()
+		switch {
+		case left <= 0:
+			slog.Info("Disconnecting idle connection", "remote", conn.RemoteAddr().String(), "player", conn.Permissions.Extensions[extPlayer])
+			for _, s := range sessions.on(conn) {
+				s.kick("idle", fmt.Sprintf("Disconnected after %s without a key being pressed.", timeout))
+			}
+			conn.Close()
+			return
+		case left <= warn:
+			for _, s := range sessions.on(conn) {
+				s.notify(fmt.Sprintf("Still there? Press a key or you'll be disconnected in %s", left.Round(time.Second)))
+			}
+			warned = true
+		case warned:
+			for _, s := range sessions.on(conn) {
+				s.notify("")
+			}
+			warned = false
+		}
+	}
+}
+
+// kickGrace is how long a stopped game has to put the player's screen back
+// and say goodbye before its connection is closed under it.
+const kickGrace = 3 * time.Second
+
+// kick ends the player's game, or the spectator's watching, for the given
+// reason, telling them why once their screen has been put back. A client that has stopped reading leaves
+// the game stuck writing to it, so if the game hasn't ended within kickGrace
+// the whole connection is closed. The terminal is stopped too, since a resize
+// waiting on the stuck game would otherwise keep the connection from noticing
+// it has closed and failing the write.
+func (s *session) kick(reason, goodbye string) {
+	s.mu.Lock()
+	s.reason, s.goodbye = reason, goodbye
+	s.mu.Unlock()
+	s.stop()
+	select {
+	case <-s.done:
+	case <-time.After(kickGrace):
+		s.log.Info("Closing connection to unresponsive client", "reason", reason)
+		s.conn.Close()
+		s.terminal.Stop()
+	}
+}
+
+// notify shows msg on the session's screen, or clears the last message if msg is empty.
+func (s *session) notify(msg string) {
+	if !s.spectating {
+		s.game.Notify(msg)
+		return
+	}
+	s.mu.Lock()
+	s.notice = msg
+	s.mu.Unlock()
+	select {
+	case s.noticed <- struct{}{}:
+	default:
+	}
+}
+
+// stop ends the session's game, or makes the spectator leave.
+func (s *session) stop() {
+	if !s.spectating {
+		s.game.Stop()
+		return
+	}
+	s.quitOnce.Do(func() { close(s.quit) })
+}
+
+// player returns the name the client signed in as.
+func (s *session) player() string {
+	return s.conn.Permissions.Extensions[extPlayer]
+}
+
+// exit tells the client the session is over with the given status and closes the channel.
+func (s *session) exit(status int) {
+	s.channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(status)}))
+	s.channel.Close()
+}
+
+// close stops the game or spectator if it is still running, waits for it to
+// finish and closes the channel.
+func (s *session) close() {
+	if s.game != nil || s.spectating {
+		s.mu.Lock()
+		if s.reason == "" {
+			s.reason = "disconnected"
+		}
+		s.mu.Unlock()
+		s.stop()
+		<-s.done
+	}
+	s.channel.Close()
+	s.log.Debug("Session closed")
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// spectateUser is the SSH user that watches other players' games instead of playing.
+const spectateUser = "spectate"
+
+// maxListed is how many games the spectator's list shows, one for each digit key.
+const maxListed = 9
+
+// spectate lets the client pick a game being played and watch it, until they
+// quit or are made to leave. Spectators count towards --max-sessions like players.
+func (s *session) spectate() {
+	defer close(s.done)
+	s.started = time.Now()
+	if err := sessions.add(s); err != nil {
+		s.log.Info("Turned away", "reason", err)
+		fmt.Fprintf(s.channel, "Sorry, %s.\r\n", err)
+		s.exit(1)
+		return
+	}
+	defer sessions.remove(s)
+	s.log.Info("Spectating")
+
+	// Keys are read here the whole time, so watching never sends the player any input
+	keys := make(chan byte)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 256)
+		for {
+			n, err := s.channel.Read(buf)
+			for _, k := range buf[:n] {
+				select {
+				case keys <- k:
+				case <-done:
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	s.browse(keys)
+
+	s.mu.Lock()
+	switch {
+	case s.reason != "":
+	case s.channel.eof.Load():
+		s.reason = "disconnected"
+	default:
+		s.reason = "quit"
+	}
+	if s.goodbye != "" {
+		fmt.Fprintf(s.channel, "%s\r\n", s.goodbye)
+	}
+	reason := s.reason
+	s.mu.Unlock()
+	s.log.Info("Stopped spectating", "reason", reason, "duration", time.Since(s.started).Round(time.Second).String())
+	s.exit(0)
+}
+
+// browse shows the spectator the games they can watch and lets them pick
+// one, until they quit or are made to leave.
+func (s *session) browse(keys <-chan byte) {
+	refresh := time.NewTicker(2 * time.Second)
+	defer refresh.Stop()
+	notice := ""
+	for {
+		list := sessions.games()
+		if len(list) > maxListed {
+			list = list[:maxListed]
+		}
+		// The server's notices, such as the shutdown countdown, come first
+		shown := notice
+		s.mu.Lock()
+		if s.notice != "" {
+			shown = s.notice
+		}
+		s.mu.Unlock()
+		s.drawGames(list, shown)
+
+		select {
+		case <-refresh.C:
+		case <-s.noticed:
+		case <-s.quit:
+			return
+		case k, ok := <-keys:
+			switch {
+			case !ok || k == 'q' || k == 3 || k == 4:
+				return
+			case k >= '1' && int(k-'0') <= len(list):
+				target := list[k-'1']
+				if !s.watch(target, keys) {
+					return
+				}
+				notice = fmt.Sprintf("You stopped watching %s.", target.player())
+				select {
+				case <-target.done:
+					notice = fmt.Sprintf("%s's game has ended.", target.player())
+				default:
+				}
+			}
+		}
+	}
+}
+
+// drawGames shows the spectator the games they can watch.
+func (s *session) drawGames(list []*session, notice string) {
+	var b bytes.Buffer
+	b.WriteString("\x1b[0m\x1b[2J\x1b[H")
+	if notice != "" {
+		fmt.Fprintf(&b, "%s\r\n\r\n", notice)
+	}
+	if len(list) == 0 {
+		b.WriteString("Nobody is playing right now. Waiting for a game to start...\r\n\r\nPress Q to quit.\r\n")
+		s.channel.Write(b.Bytes())
+		return
+	}
+	b.WriteString("Games being played:\r\n\r\n")
+	for i, p := range list {
+		st := p.game.Status()
+		w, h := p.terminal.Size()
+		fmt.Fprintf(&b, "  %d  %-16.16s  %-6s  level %-3d  %3dx%-3d  %s\r\n",
+			i+1, p.player(), st.Screen, st.Level, w, h, time.Since(p.started).Round(time.Second))
+	}
+	b.WriteString("\r\nPress a number to watch that game, then Q to come back here. Q quits.\r\n" +
+		"Games are shown at the player's size, so make your window at least as big.\r\n")
+	s.channel.Write(b.Bytes())
+}
+
+// watch mirrors target's screen onto the spectator's until they press Q,
+// when it returns true, or leave or are made to, when it returns false. It
+// also returns true once target's game ends or there is a notice for the
+// spectator, which is shown back on the list of games.
+func (s *session) watch(target *session, keys <-chan byte) bool {
+	stop := target.terminal.Watch(s.channel)
+	defer stop()
+	for {
+		select {
+		case <-target.done:
+			return true
+		case <-s.quit:
+			return false
+		case <-s.noticed:
+			s.mu.Lock()
+			notice := s.notice
+			s.mu.Unlock()
+			if notice != "" {
+				return true
+			}
+		case k, ok := <-keys:
+			switch {
+			case !ok:
+				return false
+			case k == 'q' || k == 3:
+				return true
+			}
+		}
+	}
+}
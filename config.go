@@ -0,0 +1,95 @@
+package typeGopher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// configFile is the JSON layout of the config file.
+type configFile struct {
+	Difficulty string            `json:"difficulty"`
+	Presets    []json.RawMessage `json:"presets"`
+}
+
+// config holds the settings the player can change in the config file.
+type config struct {
+	difficulty string
+	presets    []difficulty
+}
+
+// defaultConfig returns the settings used when there is no config file.
+func defaultConfig() config {
+	return config{difficulty: defaultDifficulty, presets: difficultyPresets()}
+}
+
+// defaultConfigPath returns where the config file lives when no path is given.
+func defaultConfigPath() (string, error) {
+	return configFilePath("config.json")
+}
+
+// loadConfig reads the config file at path on top of the defaults. Presets in
+// the file only need to set the fields they change from the built-in preset
+// with the same name, or from "normal" for new presets. A missing file is only
+// an error when mustExist is set.
+func loadConfig(path string, mustExist bool) (config, error) {
+	cfg := defaultConfig()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !mustExist {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	var f configFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return cfg, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if f.Difficulty != "" {
+		cfg.difficulty = f.Difficulty
+	}
+	for i, raw := range f.Presets {
+		var named struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(raw, &named); err != nil || named.Name == "" {
+			return cfg, fmt.Errorf("reading %s: preset %d has no name", path, i)
+		}
+		base, err := cfg.preset(named.Name)
+		if err != nil {
+			base, _ = cfg.preset(defaultDifficulty)
+		}
+		if err := json.Unmarshal(raw, &base); err != nil {
+			return cfg, fmt.Errorf("reading %s: preset %q: %w", path, named.Name, err)
+		}
+		if err := base.validate(); err != nil {
+			return cfg, fmt.Errorf("reading %s: preset %q: %w", path, named.Name, err)
+		}
+		cfg.setPreset(base)
+	}
+	return cfg, nil
+}
+
+// preset returns the difficulty preset with the given name.
+func (c *config) preset(name string) (difficulty, error) {
+	for _, d := range c.presets {
+		if d.Name == name {
+			return d, nil
+		}
+	}
+	return difficulty{}, fmt.Errorf("unknown difficulty %q", name)
+}
+
+// setPreset adds a difficulty preset, replacing any with the same name.
+func (c *config) setPreset(d difficulty) {
+	for i := range c.presets {
+		if c.presets[i].Name == d.Name {
+			c.presets[i] = d
+			return
+		}
+	}
+	c.presets = append(c.presets, d)
+}
@@ -0,0 +1,48 @@
+package typeGopher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigPresets(t *testing.T) {
+	tests := []struct {
+		name   string
+		preset string
+		// wantErr is part of the error expected, or empty if the preset is fine.
+		wantErr string
+	}{
+		{"tuned built-in", `{"name": "easy", "base_words": 2}`, ""},
+		{"new preset", `{"name": "mine", "base_velocity": 0.5, "min_word_length": 3, "max_word_length": 5}`, ""},
+		{"no length limit", `{"name": "mine", "min_word_length": 8}`, ""},
+		{"no name", `{"base_words": 2}`, "has no name"},
+		{"no words", `{"name": "mine", "base_words": 0}`, "base_words"},
+		{"negative words", `{"name": "mine", "base_words": -1}`, "base_words"},
+		{"fewer words each level", `{"name": "mine", "words_per_level": -1}`, "words_per_level"},
+		{"rising words", `{"name": "mine", "base_velocity": -1}`, "base_velocity"},
+		{"slowing words", `{"name": "mine", "velocity_per_level": -0.1}`, "velocity_per_level"},
+		{"negative top speed", `{"name": "mine", "max_velocity": -1}`, "max_velocity"},
+		{"negative length", `{"name": "mine", "min_word_length": -1}`, "negative"},
+		{"shortest longer than longest", `{"name": "easy", "min_word_length": 6}`, "min_word_length"},
+		{"shrinking words", `{"name": "mine", "word_length_per_level": -1}`, "word_length_per_level"},
+		{"unknown penalty", `{"name": "mine", "mistake_penalty": "fine"}`, "unknown mistake penalty"},
+		{"negative cost", `{"name": "mine", "mistake_penalty": "money", "mistake_cost": -5}`, "mistake_cost"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(`{"presets": [`+tt.preset+`]}`), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadConfig(path, true)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("loadConfig() = %v, want no error", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("loadConfig() = %v, want an error about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
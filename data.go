@@ -0,0 +1,36 @@
+package typeGopher
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// embeddedData holds a copy of the data directory so the game runs from any working directory.
+//
+//go:embed data
+var embeddedData embed.FS
+
+// readData reads a file from the data directory, preferring a copy on disk
+// next to the working directory and falling back to the embedded one.
+func readData(name string) ([]byte, error) {
+	p := path.Join("data", name)
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return embeddedData.ReadFile(p)
+	}
+	return data, err
+}
+
+// readDataDir lists a directory in the data directory, preferring the one on
+// disk and falling back to the embedded one.
+func readDataDir(name string) ([]fs.DirEntry, error) {
+	p := path.Join("data", name)
+	entries, err := os.ReadDir(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return embeddedData.ReadDir(p)
+	}
+	return entries, err
+}
@@ -0,0 +1,168 @@
+package typeGopher
+
+import (
+	"fmt"
+	"math"
+)
+
+// difficulty describes how the levels get harder as the player progresses.
+// Every "PerLevel" field is added once for each level already completed.
+type difficulty struct {
+	Name string `json:"name"`
+
+	// Words on the first level.
+	BaseWords     int     `json:"base_words"`
+	WordsPerLevel float64 `json:"words_per_level"`
+
+	// How fast words fall, in rows per second. A MaxVelocity of 0 means no limit.
+	BaseVelocity     float64 `json:"base_velocity"`
+	VelocityPerLevel float64 `json:"velocity_per_level"`
+	MaxVelocity      float64 `json:"max_velocity"`
+
+	// Longest word allowed in characters. A MaxWordLength of 0 means no limit.
+	MinWordLength      int     `json:"min_word_length"`
+	MaxWordLength      int     `json:"max_word_length"`
+	WordLengthPerLevel float64 `json:"word_length_per_level"`
+
+	// Money paid for winning the first level, multiplied by RewardGrowth for every level completed.
+	BaseReward   int     `json:"base_reward"`
+	RewardGrowth float64 `json:"reward_growth"`
+
+	// What a typing mistake costs: one of the penalty constants, speeding up
+	// the word if empty. MistakeCost is the money taken by penaltyMoney or the
+	// garbage added by penaltyGarbage, with a default for each if 0.
+	MistakePenalty string `json:"mistake_penalty,omitempty"`
+	MistakeCost    int    `json:"mistake_cost,omitempty"`
+}
+
+// The ways a typing mistake can be punished.
+const (
+	// penaltySpeedUp moves the word a second further along its fall.
+	penaltySpeedUp = "speed_up"
+	// penaltyMoney takes money from the player.
+	penaltyMoney = "money"
+	// penaltyGarbage adds garbage, bringing the next collection closer.
+	penaltyGarbage = "garbage"
+	// penaltyStrict throws away everything typed of the word so far.
+	penaltyStrict = "strict"
+)
+
+// validate checks that d's settings make sense.
+func (d *difficulty) validate() error {
+	switch {
+	case d.BaseWords < 1:
+		return fmt.Errorf("base_words is %d, want at least 1", d.BaseWords)
+	case d.WordsPerLevel < 0:
+		return fmt.Errorf("words_per_level %v is negative", d.WordsPerLevel)
+	case d.BaseVelocity < 0:
+		return fmt.Errorf("base_velocity %v is negative, so words would never fall", d.BaseVelocity)
+	case d.VelocityPerLevel < 0:
+		return fmt.Errorf("velocity_per_level %v is negative, so words would stop falling", d.VelocityPerLevel)
+	case d.MaxVelocity < 0:
+		return fmt.Errorf("max_velocity %v is negative", d.MaxVelocity)
+	case d.MinWordLength < 0 || d.MaxWordLength < 0:
+		return fmt.Errorf("word lengths %d to %d are negative", d.MinWordLength, d.MaxWordLength)
+	case d.MaxWordLength > 0 && d.MinWordLength > d.MaxWordLength:
+		return fmt.Errorf("min_word_length %d is longer than max_word_length %d", d.MinWordLength, d.MaxWordLength)
+	case d.WordLengthPerLevel < 0:
+		return fmt.Errorf("word_length_per_level %v is negative", d.WordLengthPerLevel)
+	}
+	switch d.MistakePenalty {
+	case "", penaltySpeedUp, penaltyMoney, penaltyGarbage, penaltyStrict:
+	default:
+		return fmt.Errorf("unknown mistake penalty %q, want %s, %s, %s or %s",
+			d.MistakePenalty, penaltySpeedUp, penaltyMoney, penaltyGarbage, penaltyStrict)
+	}
+	if d.MistakeCost < 0 {
+		return fmt.Errorf("mistake_cost %d is negative", d.MistakeCost)
+	}
+	return nil
+}
+
+// mistakeCost returns the money or garbage a mistake costs under d's penalty.
+func (d *difficulty) mistakeCost() int {
+	if d.MistakeCost > 0 {
+		return d.MistakeCost
+	}
+	if d.MistakePenalty == penaltyMoney {
+		return 50
+	}
+	return 5
+}
+
+// defaultDifficulty is the preset used when none is chosen.
+const defaultDifficulty = "normal"
+
+// difficultyPresets returns the built-in difficulty presets.
+func difficultyPresets() []difficulty {
+	normal := difficulty{
+		Name:          "normal",
+		BaseWords:     1,
+		WordsPerLevel: 1,
+		BaseVelocity:  2,
+		BaseReward:    1500,
+		RewardGrowth:  1,
+	}
+	custom := normal
+	custom.Name = "custom"
+
+	return []difficulty{
+		{
+			Name:               "easy",
+			BaseWords:          1,
+			WordsPerLevel:      0.5,
+			BaseVelocity:       1.5,
+			VelocityPerLevel:   0.05,
+			MaxVelocity:        3,
+			MaxWordLength:      4,
+			WordLengthPerLevel: 1,
+			BaseReward:         1000,
+			RewardGrowth:       1.1,
+		},
+		normal,
+		{
+			Name:             "hard",
+			BaseWords:        2,
+			WordsPerLevel:    1.5,
+			BaseVelocity:     2.5,
+			VelocityPerLevel: 0.1,
+			MaxVelocity:      5,
+			MinWordLength:    4,
+			BaseReward:       2000,
+			RewardGrowth:     1.2,
+		},
+		custom,
+	}
+}
+
+// numWords returns how many words fall on the given level, counted from 0.
+func (d *difficulty) numWords(level int) int {
+	n := d.BaseWords + int(d.WordsPerLevel*float64(level))
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// velocity returns how fast words fall on the given level.
+func (d *difficulty) velocity(level int) float64 {
+	v := d.BaseVelocity + d.VelocityPerLevel*float64(level)
+	if d.MaxVelocity > 0 && v > d.MaxVelocity {
+		return d.MaxVelocity
+	}
+	return v
+}
+
+// wordLength returns the shortest and longest words allowed on the given level.
+// A longest length of 0 means any length.
+func (d *difficulty) wordLength(level int) (int, int) {
+	if d.MaxWordLength == 0 {
+		return d.MinWordLength, 0
+	}
+	return d.MinWordLength, d.MaxWordLength + int(d.WordLengthPerLevel*float64(level))
+}
+
+// reward returns the money paid for winning the given level.
+func (d *difficulty) reward(level int) int {
+	return int(float64(d.BaseReward) * math.Pow(d.RewardGrowth, float64(level)))
+}
@@ -2,10 +2,10 @@ package typeGopher
 
 import (
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
-	tl "github.com/JoelOtter/termloop"
+	tl "gopher_typer/term"
 )
 
 type endLevel struct {
@@ -22,9 +22,9 @@ type endLevel struct {
 	swapMessageTime time.Time
 }
 
-// addEndMessage adds an end message from a file at the given position.
-func (l *endLevel) addEndMessage(path string, x, y int) {
-	data, err := os.ReadFile(path)
+// addEndMessage adds an end message from a data file at the given position.
+func (l *endLevel) addEndMessage(name string, x, y int) {
+	data, err := readData(name)
 	if err != nil {
 		l.gt.console.SetText(fmt.Sprintf("Err: %+v", err))
 		return
@@ -35,7 +35,7 @@ func (l *endLevel) addEndMessage(path string, x, y int) {
 }
 
 // PrintStats displays various game statistics at the given position.
-func (l *endLevel) PrintStats(reward, x, y int) {
+func (l *endLevel) PrintStats(r reward, x, y int) {
 	msg := fmt.Sprintf("Levels Complete: %d", l.gt.stats.LevelsCompleted)
 	text := tl.NewText(x-len(msg)/2, y, msg, tl.ColorBlack, tl.ColorDefault)
 	l.AddEntity(text)
@@ -46,11 +46,24 @@ func (l *endLevel) PrintStats(reward, x, y int) {
 	l.AddEntity(text)
 	y++
 
-	msg = fmt.Sprintf("Reward: $%d", reward)
+	msg = fmt.Sprintf("Reward: $%d", r.total())
 	text = tl.NewText(x-len(msg)/2, y, msg, tl.ColorBlack, tl.ColorDefault)
 	l.AddEntity(text)
 	y++
 
+	if r.total() > 0 {
+		msg = fmt.Sprintf("Level %d: $%d  Accuracy %.0f%%: +$%d", l.gt.stats.LevelsCompleted, r.level, r.result.Accuracy()*100, r.accuracy)
+		text = tl.NewText(x-len(msg)/2, y, msg, tl.ColorBlue, tl.ColorDefault)
+		l.AddEntity(text)
+		y++
+
+		msg = fmt.Sprintf("Closest call %.1fs: +$%d  Typed %d/%d words: +$%d", r.result.ClosestCall.Seconds(), r.time,
+			r.result.PlayerWords, r.result.PlayerWords+r.result.GoroutineWords, r.typing)
+		text = tl.NewText(x-len(msg)/2, y, msg, tl.ColorBlue, tl.ColorDefault)
+		l.AddEntity(text)
+		y++
+	}
+
 	msg = fmt.Sprintf("Balance: $%d", l.gt.stats.Dollars)
 	text = tl.NewText(x-len(msg)/2, y, msg, tl.ColorBlack, tl.ColorDefault)
 	l.AddEntity(text)
@@ -66,25 +79,41 @@ func (l *endLevel) PrintStats(reward, x, y int) {
 	l.AddEntity(text)
 	y++
 
+	m := l.gt.metrics
+	msg = fmt.Sprintf("WPM: %.1f  Accuracy: %.0f%%  Reaction: %.2fs", m.WPM(), m.Accuracy()*100, m.AverageReaction().Seconds())
+	text = tl.NewText(x-len(msg)/2, y, msg, tl.ColorBlack, tl.ColorDefault)
+	l.AddEntity(text)
+	y++
+
+	if missed := m.MostMissed(5); len(missed) > 0 {
+		for i, ch := range missed {
+			if ch == " " {
+				missed[i] = "space"
+			}
+		}
+		msg = "Most missed keys: " + strings.Join(missed, " ")
+		text = tl.NewText(x-len(msg)/2, y, msg, tl.ColorBlack, tl.ColorDefault)
+		l.AddEntity(text)
+		y++
+	}
+
 	if l.win {
 		msg = fmt.Sprintf("Press N for next level or S for store")
 	} else if l.gt.stats.Lives > 0 {
 		msg = fmt.Sprintf("Press R to retry level or S for store")
 	} else {
-		msg = fmt.Sprintf("Press Enter to quit or N for new game")
+		msg = fmt.Sprintf("Press Enter to quit, N for new game or H for high scores")
 	}
 	text = tl.NewText(x-len(msg)/2, y+1, msg, tl.ColorBlack, tl.ColorDefault)
 	l.AddEntity(text)
 }
 
-// TODO implement money scaling feature based on
-/* CalculateReward calculates the reward based on the number of levels completed.
-func (l *endLevel) CalculateReward() int {
-	baseReward := 1000
-	scalingFactor := 1.5
-	reward := float64(baseReward) * math.Pow(scalingFactor, float64(l.gt.stats.LevelsCompleted))
-	return int(reward)
-} */
+// CalculateReward calculates the reward for the level just won from its number, the player's
+// accuracy, how close the words came to the floor and how many words the player typed.
+func (l *endLevel) CalculateReward() reward {
+	return calculateReward(&l.gt.difficulty, l.gt.stats.LevelsCompleted, l.gt.game.game.Result())
+}
+
 // ActivateWin sets up the end level for a winning condition.
 func (l *endLevel) ActivateWin() {
 	l.Level = tl.NewBaseLevel(tl.Cell{Bg: l.bg, Fg: l.fg})
@@ -92,9 +121,12 @@ func (l *endLevel) ActivateWin() {
 
 	l.win = true
 
-	moneyEarned := 1500
+	r := l.CalculateReward()
+	moneyEarned := r.total()
+	l.gt.metrics.level(r.result.Duration)
 	l.gt.stats.LevelsCompleted++
 	l.gt.stats.LevelsAttempted++
+	l.gt.levelsWon++
 	l.gt.stats.Dollars += moneyEarned
 	l.gt.stats.TotalEarned += moneyEarned
 	l.gt.console.SetText("")
@@ -103,11 +135,11 @@ func (l *endLevel) ActivateWin() {
 	l.AddEntity(rect)
 
 	l.endMessages = []*tl.Entity{}
-	l.addEndMessage("data/you_win_a.txt", w/2, 3)
-	l.addEndMessage("data/you_win_b.txt", w/2, 3)
+	l.addEndMessage("you_win_a.txt", w/2, 3)
+	l.addEndMessage("you_win_b.txt", w/2, 3)
 	l.AddEntity(l.endMessages[l.currentMessage])
 
-	l.PrintStats(moneyEarned, w/2, 13)
+	l.PrintStats(r, w/2, 9)
 
 	l.Activate()
 }
@@ -115,6 +147,8 @@ func (l *endLevel) ActivateWin() {
 // ActivateFail sets up the end level for a losing condition.
 func (l *endLevel) ActivateFail() {
 	l.win = false
+	result := l.gt.game.game.Result()
+	l.gt.metrics.level(result.Duration)
 	l.gt.stats.LevelsAttempted++
 	l.gt.stats.Lives--
 	if l.gt.stats.Lives == 0 {
@@ -130,11 +164,11 @@ func (l *endLevel) ActivateFail() {
 	l.AddEntity(rect)
 
 	l.endMessages = []*tl.Entity{}
-	l.addEndMessage("data/you_loose_a.txt", w/2, 3)
-	l.addEndMessage("data/you_loose_b.txt", w/2, 3)
+	l.addEndMessage("you_lose_a.txt", w/2, 3)
+	l.addEndMessage("you_lose_b.txt", w/2, 3)
 	l.AddEntity(l.endMessages[l.currentMessage])
 
-	l.PrintStats(0, w/2, 13)
+	l.PrintStats(reward{}, w/2, 9)
 
 	l.Activate()
 }
@@ -151,28 +185,30 @@ func (l *endLevel) ActivateGameOver() {
 	l.AddEntity(rect)
 
 	l.endMessages = []*tl.Entity{}
-	l.addEndMessage("data/game_over_a.txt", w/2, 3)
-	l.addEndMessage("data/game_over_b.txt", w/2, 3)
+	l.addEndMessage("game_over_a.txt", w/2, 3)
+	l.addEndMessage("game_over_b.txt", w/2, 3)
 	l.AddEntity(l.endMessages[l.currentMessage])
 
-	l.PrintStats(0, w/2, 13)
+	l.PrintStats(reward{}, w/2, 9)
 
 	l.Activate()
 }
 
-// Activate sets the end level as the active screen.
+// Activate sets the end level as the active screen and saves the game.
 func (l *endLevel) Activate() {
+	l.gt.autosave()
+	l.gt.recordScore()
 	l.gt.g.Screen().SetLevel(l)
-	l.tickWait = time.Now().Add(500 * time.Millisecond)
+	l.tickWait = l.gt.clock.Now().Add(500 * time.Millisecond)
 }
 
 // Draw updates the end level's display, including swapping end messages.
 func (l *endLevel) Draw(screen *tl.Screen) {
 	l.Level.Draw(screen)
 
-	if time.Now().After(l.swapMessageTime) {
+	if l.gt.clock.Now().After(l.swapMessageTime) {
 		lastMessage := l.currentMessage
-		l.swapMessageTime = time.Now().Add(500 * time.Millisecond)
+		l.swapMessageTime = l.gt.clock.Now().Add(500 * time.Millisecond)
 		l.currentMessage = (l.currentMessage + 1) % len(l.endMessages)
 		l.RemoveEntity(l.endMessages[lastMessage])
 		l.AddEntity(l.endMessages[l.currentMessage])
@@ -182,13 +218,19 @@ func (l *endLevel) Draw(screen *tl.Screen) {
 
 // Tick handles user input to navigate to the next level or store.
 func (l *endLevel) Tick(e tl.Event) {
-	if time.Now().After(l.tickWait) && e.Type == tl.EventKey {
+	if !l.gt.handleInput(e) {
+		return
+	}
+	if l.gt.clock.Now().After(l.tickWait) && e.Type == tl.EventKey {
 		if e.Ch == 'N' || e.Ch == 'n' || e.Ch == 'R' || e.Ch == 'r' {
 			l.gt.g.SetEndKey(tl.KeyCtrlC)
 			l.gt.goToGame()
 		} else if e.Ch == 'S' || e.Ch == 's' {
 			l.gt.g.SetEndKey(tl.KeyCtrlC)
 			l.gt.goToStore()
+		} else if (e.Ch == 'H' || e.Ch == 'h') && l.gt.stats.Lives == 0 {
+			l.gt.g.SetEndKey(tl.KeyCtrlC)
+			l.gt.goToScores()
 		}
 	}
 }
@@ -0,0 +1,392 @@
+package typeGopher
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// Input is a single key press delivered to the simulation.
+type Input struct {
+	Ch rune
+	// Action, if set, is a targeting key pressed instead of the character Ch.
+	Action Action
+}
+
+// Action is a key that changes which word the player is typing rather than typing into it.
+type Action int
+
+const (
+	// NoAction means the input is the character Ch.
+	NoAction Action = iota
+	// Abandon drops the player's current word, undoing their progress on it.
+	Abandon
+	// NextTarget moves the player's progress onto the next word that starts the same way.
+	NextTarget
+	// Erase removes the last wrong character typed into the current word, or
+	// abandons the word if there are none.
+	Erase
+)
+
+// Result sums up how a level has gone so far.
+type Result struct {
+	Keystrokes     int
+	Mistakes       int
+	PlayerWords    int
+	GoroutineWords int
+	// ClosestCall is the shortest time any word had left before hitting the floor when it was completed.
+	ClosestCall time.Duration
+	// Duration is how much game time the level has lasted.
+	Duration time.Duration
+}
+
+// Accuracy returns how much of the level's typing was correct, as used for its reward.
+func (r Result) Accuracy() float64 {
+	return accuracy(r.Keystrokes, r.Mistakes)
+}
+
+// Game is the headless simulation of a single level. It owns the falling
+// words, the player's stats and the purchased items, and advances them with
+// Step without depending on any terminal or renderer.
+type Game struct {
+	words                []*word
+	currentWord          *word
+	stats                *stats
+	items                []item
+	rng                  *rand.Rand
+	metrics              *Metrics
+	width, height        int
+	now                  time.Time
+	started              time.Time
+	garbageCollectEndsAt time.Time
+	won, lost            bool
+	result               Result
+	// readyAt is when the player was last free to pick a new word.
+	readyAt time.Time
+	// penalty and mistakeCost say what a typing mistake costs.
+	penalty     string
+	mistakeCost int
+}
+
+// NewGame lays out words on an empty field of the given size, falling at
+// velocity rows per second from start, for a new player with no items. Tests,
+// bots and other front ends can drive it with Step and read it with Words and Target.
+func NewGame(words []string, velocity float64, width, height int, seed int64, start time.Time) *Game {
+	s := newStats()
+	return layoutGame(&s, nil, words, velocity, &difficulty{}, rand.New(rand.NewSource(seed)), newMetrics(start), width, height, start)
+}
+
+// newGame lays out a fresh level of words on a field of the given size, as hard as d makes the player's next level.
+func newGame(s *stats, items []item, pack *wordPack, d *difficulty, rng *rand.Rand, m *Metrics, width, height int, start time.Time) *Game {
+	level := s.LevelsCompleted
+	minLen, maxLen := d.wordLength(level)
+	words := make([]string, d.numWords(level))
+	for i := range words {
+		words[i] = pack.pickLength(rng, minLen, maxLen)
+	}
+	return layoutGame(s, items, words, d.velocity(level), d, rng, m, width, height, start)
+}
+
+// layoutGame places words in rows across a field of the given size, all falling at v rows per second.
+func layoutGame(s *stats, items []item, words []string, v float64, d *difficulty, rng *rand.Rand, m *Metrics, width, height int, start time.Time) *Game {
+	g := &Game{stats: s, items: items, rng: rng, metrics: m, width: width, height: height, now: start, started: start, readyAt: start}
+	g.stats.Garbage = 0
+	g.penalty, g.mistakeCost = d.MistakePenalty, d.mistakeCost()
+
+	x := 0
+	y := 0
+	rowHeight := 1
+	for _, str := range words {
+		w := newWord(0, 0, str, v, width, start)
+		ww, wh := w.Size()
+		if ww+x > width {
+			x = 0
+			y += rowHeight
+			rowHeight = 1
+		}
+		w.x, w.y, w.baseY = x, y, y
+		if wh > rowHeight {
+			rowHeight = wh
+		}
+		g.words = append(g.words, w)
+		x += ww + 2
+	}
+
+	return g
+}
+
+// Step advances the simulation by dt after applying the given inputs to the
+// player's current word.
+func (g *Game) Step(dt time.Duration, inputs []Input) {
+	if g.Over() {
+		return
+	}
+	for _, in := range inputs {
+		switch in.Action {
+		case Abandon:
+			g.abandon()
+		case NextTarget:
+			g.nextTarget()
+		case Erase:
+			if cw := g.currentWord; cw == nil || !cw.Erase() {
+				g.abandon()
+			}
+		default:
+			g.keyDown(in.Ch)
+		}
+	}
+	g.now = g.now.Add(dt)
+
+	if !g.CollectingGarbage() {
+		for _, i := range g.items {
+			i.Tick(g)
+		}
+	}
+
+	totalComplete := 0
+	for _, w := range g.words {
+		w.Update(g.now)
+		_, wh := w.Size()
+		if !w.Complete() && w.y+wh-1 > g.height-3 {
+			g.lost = true
+		}
+		if w.Complete() {
+			if !w.finished {
+				w.finished = true
+				g.recordFinish(w)
+			}
+			totalComplete++
+		}
+	}
+	if totalComplete == len(g.words) {
+		g.won = true
+	}
+	if g.currentWord != nil && g.currentWord.Complete() {
+		g.currentWord = nil
+		g.readyAt = g.now
+	}
+
+	if g.stats.GarbageCollect(g.rng) {
+		g.stats.Garbage = 0
+		if g.stats.GoVersion < 1.5 {
+			g.garbageCollectEndsAt = g.now.Add(time.Second * 3)
+		} else {
+			g.garbageCollectEndsAt = g.now.Add(time.Second)
+		}
+	}
+}
+
+// keyDown types ch into the player's current word. Without one, ch picks
+// the lowest word starting with it that nobody has started; keys that start
+// no word are ignored.
+func (g *Game) keyDown(ch rune) {
+	cw := g.currentWord
+	if cw == nil {
+		candidates := g.candidates([]rune{ch})
+		if len(candidates) == 0 {
+			return
+		}
+		cw = candidates[0]
+		cw.startedBy = pc
+		cw.targetedAt = g.readyAt
+		g.currentWord = cw
+	}
+	// Keys past the end of the word, with mistakes still to erase, go nowhere
+	at := cw.completedChars + len(cw.wrong)
+	if at >= len(cw.chars) {
+		return
+	}
+	if cw.firstKeyAt.IsZero() {
+		cw.firstKeyAt = g.now
+	}
+	expected := cw.chars[at]
+	correct := cw.KeyDown(ch)
+	g.result.Keystrokes++
+	if !correct {
+		g.result.Mistakes++
+		cw.mistakes++
+		g.penalize(cw)
+	}
+	g.metrics.keystroke(expected, correct)
+}
+
+// penalize makes the player pay for a mistake typing w.
+func (g *Game) penalize(w *word) {
+	switch g.penalty {
+	case penaltyMoney:
+		g.stats.Dollars -= g.mistakeCost
+		if g.stats.Dollars < 0 {
+			g.stats.Dollars = 0
+		}
+	case penaltyGarbage:
+		g.stats.Garbage += g.mistakeCost
+	case penaltyStrict:
+		w.completedChars = 0
+		w.wrong = nil
+	default:
+		w.createdAt = w.createdAt.Add(-1 * time.Second)
+	}
+}
+
+// abandon drops the player's current word, leaving it as if it had never been started.
+func (g *Game) abandon() {
+	if cw := g.currentWord; cw != nil {
+		cw.untarget()
+		g.currentWord = nil
+		g.readyAt = g.now
+	}
+}
+
+// nextTarget moves the player's progress on their current word onto the
+// next lowest word that starts with what they have typed correctly, going
+// back to the lowest after the highest. Mistakes still to erase are dropped.
+func (g *Game) nextTarget() {
+	cw := g.currentWord
+	if cw == nil {
+		return
+	}
+	words := append(g.candidates(cw.chars[:cw.completedChars]), cw)
+	if len(words) == 1 {
+		return
+	}
+	sortLowestFirst(words)
+	var next *word
+	for i, w := range words {
+		if w == cw {
+			next = words[(i+1)%len(words)]
+		}
+	}
+
+	next.startedBy = pc
+	next.completedChars = cw.completedChars
+	next.targetedAt, next.firstKeyAt, next.mistakes = cw.targetedAt, cw.firstKeyAt, cw.mistakes
+	cw.untarget()
+	g.currentWord = next
+}
+
+// candidates returns the unfinished words nobody has started that begin with
+// prefix, lowest on screen first.
+func (g *Game) candidates(prefix []rune) []*word {
+	var words []*word
+	for _, w := range g.words {
+		if w.startedBy == 0 && !w.Complete() && w.hasPrefix(prefix) {
+			words = append(words, w)
+		}
+	}
+	sortLowestFirst(words)
+	return words
+}
+
+// sortLowestFirst orders words by how close their bottom is to the floor,
+// keeping the order of words at the same height.
+func sortLowestFirst(words []*word) {
+	sort.SliceStable(words, func(i, j int) bool {
+		_, hi := words[i].Size()
+		_, hj := words[j].Size()
+		return words[i].y+hi > words[j].y+hj
+	})
+}
+
+// recordFinish adds a just completed word to the level's result.
+func (g *Game) recordFinish(w *word) {
+	if w.startedBy == pc {
+		g.result.PlayerWords++
+		g.metrics.word(WordMetrics{
+			Word:     w.str,
+			Reaction: w.firstKeyAt.Sub(w.targetedAt),
+			Duration: g.now.Sub(w.targetedAt),
+			Errors:   w.mistakes,
+		})
+	} else {
+		g.result.GoroutineWords++
+	}
+	left := w.timeToFloor(g.now, g.height-3)
+	if g.result.PlayerWords+g.result.GoroutineWords == 1 || left < g.result.ClosestCall {
+		g.result.ClosestCall = left
+	}
+}
+
+// Result returns how the level has gone so far.
+func (g *Game) Result() Result {
+	r := g.result
+	r.Duration = g.now.Sub(g.started)
+	return r
+}
+
+// CollectingGarbage reports whether the garbage collector is currently pausing the goroutines.
+func (g *Game) CollectingGarbage() bool {
+	return g.now.Before(g.garbageCollectEndsAt)
+}
+
+// GarbageRemaining returns how long the current garbage collection pause will last.
+func (g *Game) GarbageRemaining() time.Duration {
+	return g.garbageCollectEndsAt.Sub(g.now)
+}
+
+// Won reports whether every word has been completed.
+func (g *Game) Won() bool {
+	return g.won && !g.lost
+}
+
+// Lost reports whether an unfinished word has reached the floor.
+func (g *Game) Lost() bool {
+	return g.lost
+}
+
+// WordState is how a falling word looks at a point in the game.
+type WordState struct {
+	Text string
+	// Lines is Text wrapped to the width of the field, with its first line starting at X, Y.
+	Lines []string
+	X, Y  int
+	// Typed is how many runes of Text have been typed, and Wrong the mistakes typed after them.
+	Typed int
+	Wrong string
+	// Player is set while the player is typing the word, Goroutine while one of their goroutines is.
+	Player, Goroutine bool
+	Complete          bool
+}
+
+// Words returns the state of every word in the level, in the order they were laid out.
+func (g *Game) Words() []WordState {
+	words := make([]WordState, len(g.words))
+	for i, w := range g.words {
+		words[i] = WordState{
+			Text:      w.str,
+			Lines:     append([]string(nil), w.lines...),
+			X:         w.x,
+			Y:         w.y,
+			Typed:     w.completedChars,
+			Wrong:     string(w.wrong),
+			Player:    w.startedBy == pc,
+			Goroutine: w.startedBy > 0,
+			Complete:  w.Complete(),
+		}
+	}
+	return words
+}
+
+// Target returns the index in Words of the word the player is typing, or -1 if they haven't picked one.
+func (g *Game) Target() int {
+	for i, w := range g.words {
+		if w == g.currentWord {
+			return i
+		}
+	}
+	return -1
+}
+
+// Size returns the width and height of the field the words fall on.
+func (g *Game) Size() (int, int) {
+	return g.width, g.height
+}
+
+// Now returns the game time the simulation has reached.
+func (g *Game) Now() time.Time {
+	return g.now
+}
+
+// Over reports whether the level has been won or lost.
+func (g *Game) Over() bool {
+	return g.won || g.lost
+}
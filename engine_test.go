@@ -0,0 +1,217 @@
+package typeGopher
+
+import (
+	"testing"
+	"time"
+)
+
+// testStep is how much game time each step covers in these tests, chosen so
+// the steps land exactly on the times being checked.
+const testStep = 100 * time.Millisecond
+
+// play drives g with clock in testStep steps until the given game time,
+// typing keys[at] at the start of the step that begins at game time at.
+func play(g *Game, clock *FakeClock, start time.Time, until time.Duration, keys map[time.Duration]string) {
+	for clock.Now().Sub(start) < until {
+		var inputs []Input
+		for _, ch := range keys[clock.Now().Sub(start)] {
+			inputs = append(inputs, Input{Ch: ch})
+		}
+		clock.Advance(testStep)
+		g.Step(clock.Now().Sub(g.Now()), inputs)
+	}
+}
+
+// goroutine returns a goroutine that types a character every wait exactly.
+func goroutine(wait time.Duration) *goroutineItem {
+	i := newGoroutineItem(1, wait)
+	i.SetID(1)
+	return i
+}
+
+func TestGameStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		velocity float64
+		// setup, if set, changes the game before it starts.
+		setup func(g *Game)
+		keys  map[time.Duration]string
+		until time.Duration
+
+		wantY         int
+		wantTyped     int
+		wantPlayer    bool
+		wantGoroutine bool
+		wantWon       bool
+		wantLost      bool
+		wantGC        bool
+	}{
+		{
+			name:     "falls at its velocity",
+			words:    []string{"go"},
+			velocity: 2,
+			until:    2500 * time.Millisecond,
+			wantY:    5,
+		},
+		{
+			name:     "still above the floor",
+			words:    []string{"go"},
+			velocity: 2,
+			until:    3900 * time.Millisecond,
+			wantY:    7,
+		},
+		{
+			name:     "lost on passing the floor",
+			words:    []string{"go"},
+			velocity: 2,
+			until:    4 * time.Second,
+			wantY:    8,
+			wantLost: true,
+		},
+		{
+			name:       "typing targets the word",
+			words:      []string{"gopher"},
+			keys:       map[time.Duration]string{0: "go"},
+			until:      time.Second,
+			wantTyped:  2,
+			wantPlayer: true,
+		},
+		{
+			name:       "typing every word wins",
+			words:      []string{"go"},
+			velocity:   2,
+			keys:       map[time.Duration]string{0: "g", time.Second: "o"},
+			until:      2 * time.Second,
+			wantY:      2,
+			wantTyped:  2,
+			wantPlayer: true,
+			wantWon:    true,
+		},
+		{
+			name:  "goroutine sleeps before waking",
+			words: []string{"gopher"},
+			setup: func(g *Game) { g.items = []item{goroutine(time.Second)} },
+			until: time.Second,
+		},
+		{
+			name:          "goroutine types a character each time it wakes",
+			words:         []string{"gopher"},
+			setup:         func(g *Game) { g.items = []item{goroutine(time.Second)} },
+			until:         3500 * time.Millisecond,
+			wantTyped:     3,
+			wantGoroutine: true,
+		},
+		{
+			name:          "goroutine finishes the level",
+			words:         []string{"go"},
+			setup:         func(g *Game) { g.items = []item{goroutine(time.Second)} },
+			until:         2500 * time.Millisecond,
+			wantTyped:     2,
+			wantGoroutine: true,
+			wantWon:       true,
+		},
+		{
+			name:  "garbage collection pauses goroutines",
+			words: []string{"gopher"},
+			setup: func(g *Game) {
+				g.items = []item{goroutine(500 * time.Millisecond)}
+				g.stats.Garbage = 1 << 30
+			},
+			until:  2900 * time.Millisecond,
+			wantGC: true,
+		},
+		{
+			name:  "goroutines carry on after the pause",
+			words: []string{"gopher"},
+			setup: func(g *Game) {
+				g.items = []item{goroutine(500 * time.Millisecond)}
+				g.stats.Garbage = 1 << 30
+			},
+			until:         3500 * time.Millisecond,
+			wantTyped:     1,
+			wantGoroutine: true,
+		},
+		{
+			name:  "newer Go collects faster",
+			words: []string{"gopher"},
+			setup: func(g *Game) {
+				g.items = []item{goroutine(500 * time.Millisecond)}
+				g.stats.Garbage = 1 << 30
+				g.stats.GoVersion = 1.5
+			},
+			until:         1500 * time.Millisecond,
+			wantTyped:     1,
+			wantGoroutine: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Date(2016, 1, 24, 0, 0, 0, 0, time.UTC)
+			clock := NewFakeClock(start)
+			g := NewGame(tt.words, tt.velocity, 80, 10, 1, start)
+			if tt.setup != nil {
+				tt.setup(g)
+			}
+			play(g, clock, start, tt.until, tt.keys)
+
+			if !g.Over() && !g.Now().Equal(clock.Now()) {
+				t.Errorf("game time %v, want the clock's %v", g.Now(), clock.Now())
+			}
+			w := g.Words()[0]
+			if w.Y != tt.wantY {
+				t.Errorf("Y = %d, want %d", w.Y, tt.wantY)
+			}
+			if w.Typed != tt.wantTyped {
+				t.Errorf("Typed = %d, want %d", w.Typed, tt.wantTyped)
+			}
+			if w.Player != tt.wantPlayer || w.Goroutine != tt.wantGoroutine {
+				t.Errorf("typed by player %v, goroutine %v, want %v, %v", w.Player, w.Goroutine, tt.wantPlayer, tt.wantGoroutine)
+			}
+			if g.Won() != tt.wantWon || g.Lost() != tt.wantLost {
+				t.Errorf("won %v, lost %v, want %v, %v", g.Won(), g.Lost(), tt.wantWon, tt.wantLost)
+			}
+			if g.CollectingGarbage() != tt.wantGC {
+				t.Errorf("collecting garbage %v, want %v", g.CollectingGarbage(), tt.wantGC)
+			}
+		})
+	}
+}
+
+func TestGameTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		words      []string
+		inputs     []Input
+		wantTarget int
+		wantTyped  int
+	}{
+		{"nothing typed", []string{"go", "gopher"}, nil, -1, 0},
+		{"no word starts with the key", []string{"go", "gopher"}, []Input{{Ch: 'x'}}, -1, 0},
+		{"first letter picks the first of equally low words", []string{"go", "gopher"}, []Input{{Ch: 'g'}}, 0, 1},
+		{"first letter picks a matching word", []string{"rust", "go"}, []Input{{Ch: 'g'}}, 1, 1},
+		{"tab moves progress to the next word", []string{"go", "gopher"}, []Input{{Ch: 'g'}, {Ch: 'o'}, {Action: NextTarget}}, 1, 2},
+		{"tab wraps back around", []string{"go", "gopher"}, []Input{{Ch: 'g'}, {Action: NextTarget}, {Action: NextTarget}}, 0, 1},
+		{"abandon drops the word", []string{"go"}, []Input{{Ch: 'g'}, {Action: Abandon}}, -1, 0},
+		{"erase removes a mistake", []string{"gopher"}, []Input{{Ch: 'g'}, {Ch: 'x'}, {Action: Erase}, {Ch: 'o'}}, 0, 2},
+		{"erase without mistakes drops the word", []string{"gopher"}, []Input{{Ch: 'g'}, {Action: Erase}}, -1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Date(2016, 1, 24, 0, 0, 0, 0, time.UTC)
+			g := NewGame(tt.words, 0, 80, 10, 1, start)
+			g.Step(testStep, tt.inputs)
+
+			target := g.Target()
+			if target != tt.wantTarget {
+				t.Fatalf("Target() = %d, want %d", target, tt.wantTarget)
+			}
+			if target < 0 {
+				return
+			}
+			if w := g.Words()[target]; w.Typed != tt.wantTyped || !w.Player {
+				t.Errorf("target typed %d by player %v, want %d by the player", w.Typed, w.Player, tt.wantTyped)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package typeGopher
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ConfigDir returns the directory the game keeps its config, saves and high scores in.
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gopher_typer"), nil
+}
+
+// configFilePath returns the path of the named file in ConfigDir.
+func configFilePath(name string) (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// writeFileAtomic writes data to path, creating its directory if needed. The
+// old file is only replaced once the new one is complete, and writers racing
+// each other each use their own temporary file.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
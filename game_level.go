@@ -3,122 +3,100 @@ package typeGopher
 import (
 	"fmt"
 	"math"
-	"math/rand"
 	"strings"
 	"time"
 
-	tl "github.com/JoelOtter/termloop"
+	"github.com/mattn/go-runewidth"
+	tl "gopher_typer/term"
 )
 
+// stepSize is how much game time each step of the simulation covers.
+const stepSize = time.Second / 30
+
+// pendingInput is a key press waiting to be applied at the step covering its time.
+type pendingInput struct {
+	in Input
+	at time.Time
+}
+
 type gameLevel struct {
 	tl.Level
-	gt                   *GopherTyper
-	fg                   tl.Attr
-	bg                   tl.Attr
-	words                []*word
-	currentWord          *word
-	currentWordText      *tl.Text
-	garbageText          *tl.Text
-	garbageCollectEndsAt time.Time
+	gt              *GopherTyper
+	fg              tl.Attr
+	bg              tl.Attr
+	game            *Game
+	inputs          []pendingInput
+	lastStep        time.Time
+	currentWordText *tl.Text
+	garbageText     *tl.Text
+	statusText      *tl.Text
+	// racer is the player's place in a race, or nil when playing alone.
+	racer *racer
+	// paused is set while the pause menu is open, with pauseChoice its selected entry.
+	paused      bool
+	pauseChoice int
+	// startStats are the player's stats as the level began, for restarting it.
+	startStats stats
 }
 
-// Activate sets up the game level, creating and displaying the required words.
+// Activate sets up the game level, starting a new simulation and the text used to display it.
 func (l *gameLevel) Activate() {
 	l.Level = tl.NewBaseLevel(tl.Cell{Bg: l.bg, Fg: l.fg})
 
-	l.gt.stats.Garbage = 0
-
 	l.gt.game.AddEntity(&l.gt.console)
 	l.gt.console.SetText("")
 
-	numWords := l.gt.stats.LevelsCompleted + 1
-	w, h := l.gt.g.Screen().Size()
-	l.words = []*word{}
-
-	x := 0
-	y := 0
-	for i := 0; i < numWords; i++ {
-		str := l.gt.wordList[rand.Intn(len(l.gt.wordList))]
-		if len(str)+x > w {
-			x = 0
-			y++
+	// Everyone in a race plays at full speed
+	if l.racer != nil {
+		l.gt.clock.SetSpeed(1)
+	} else {
+		l.gt.setSpeed(l.gt.speed)
+	}
+	l.paused = false
+	l.startStats = l.gt.stats
+
+	w, h := l.gt.fieldSize()
+	l.lastStep = l.gt.clock.Now()
+	if l.racer != nil {
+		w, h = raceWidth, raceHeight
+		l.game = l.racer.room.newGame(l.gt.metrics, l.lastStep)
+	} else {
+		for _, i := range l.gt.items {
+			i.Reset(l.gt)
 		}
-		w := newWord(x, y, str, tl.ColorRed, tl.ColorGreen, tl.ColorBlue, tl.ColorCyan)
-		l.AddEntity(w)
-		l.words = append(l.words, w)
-		x += len(w.str) + 2
+		l.game = newGame(&l.gt.stats, l.gt.items, l.gt.pack, &l.gt.difficulty, l.gt.rng, l.gt.metrics, w, h, l.lastStep)
 	}
-	l.currentWord = nil
+	l.inputs = nil
+
 	l.currentWordText = tl.NewText(0, h-1, "", tl.ColorRed, tl.ColorBlue)
 	l.AddEntity(l.currentWordText)
 
 	l.garbageText = tl.NewText(w, h-1, "", tl.ColorRed, tl.ColorBlue)
 	l.AddEntity(l.garbageText)
 
-	l.AddEntity(tl.NewText(0, h-2, strings.Repeat("*", w), tl.ColorBlack, tl.ColorDefault))
-	for _, i := range l.gt.items {
-		i.Reset(l.gt)
-	}
+	l.statusText = tl.NewText(0, h-2, strings.Repeat("*", w), tl.ColorBlack, tl.ColorDefault)
+	l.AddEntity(l.statusText)
 
 	l.gt.g.Screen().SetLevel(l)
 }
 
-// Draw updates the game level's display and checks for game won or lost conditions.
+// Draw steps the simulation, renders its state and checks for game won or lost conditions.
 func (l *gameLevel) Draw(screen *tl.Screen) {
 	l.Level.Draw(screen)
 
-	if time.Now().After(l.garbageCollectEndsAt) {
-		for _, i := range l.gt.items {
-			i.Tick(l)
-		}
-	}
+	l.step(l.gt.clock.Now())
 
-	sw, sh := screen.Size()
-	gameLost := false
-	gameWon := false
-	totalComplete := 0
-	for _, w := range l.words {
-		w.Update()
-		if !w.Complete() && w.y > sh-3 {
-			gameLost = true
-		}
-		if w.Complete() {
-			totalComplete++
-		}
-	}
-	if totalComplete == len(l.words) {
-		gameWon = true
-	}
-	if l.currentWord != nil && l.currentWord.Complete() {
-		l.currentWord = nil
+	for _, w := range l.game.words {
+		l.drawWord(screen, w)
 	}
-	var possibleWords []int
-	for i, w := range l.words {
-		if !w.Complete() && w.startedBy == 0 {
-			possibleWords = append(possibleWords, i)
-		}
-	}
-
-	if l.currentWord == nil && len(possibleWords) > 0 {
-		l.currentWord = l.words[possibleWords[rand.Intn(len(possibleWords))]]
-		l.currentWord.startedBy = pc
-	}
-
-	if l.gt.stats.GarbageCollect() {
-		l.gt.stats.Garbage = 0
-		if l.gt.stats.GoVersion < 1.5 {
-			l.garbageCollectEndsAt = time.Now().Add(time.Second * 3)
-		} else {
-			l.garbageCollectEndsAt = time.Now().Add(time.Second)
-		}
 
-	}
+	sw, sh := screen.Size()
 	var msg string
-	if time.Now().Before(l.garbageCollectEndsAt) {
+	if l.game.CollectingGarbage() {
 		msg = fmt.Sprintf("COLLECTING GARBAGE: PLEASE WAIT")
 		l.garbageText.SetText(msg)
 		bgColor := tl.ColorBlue
-		if math.Remainder(float64(time.Now().Sub(l.garbageCollectEndsAt)), float64(time.Second)) > 0.5 {
+		if math.Remainder(float64(-l.game.GarbageRemaining()), float64(time.Second)) > 0.5 {
 			bgColor = tl.ColorBlack
 		}
 		l.garbageText.SetColor(tl.ColorRed, bgColor)
@@ -130,26 +108,126 @@ func (l *gameLevel) Draw(screen *tl.Screen) {
 		l.garbageText.SetPosition(sw-len(msg), sh-1)
 	}
 
-	if l.currentWord != nil {
-		l.currentWordText.SetText("Current Word: " + l.currentWord.str[l.currentWord.completedChars:])
+	if cw := l.game.currentWord; cw != nil {
+		l.currentWordText.SetText("Current Word: " + cw.remaining())
 	} else {
-		l.currentWordText.SetText("")
+		l.currentWordText.SetText("Type the first letter of a word to target it")
+	}
+	if l.paused {
+		l.drawPause(screen)
+		return
+	}
+	if l.racer != nil {
+		l.drawRace()
+		return
 	}
 	// End conditions
-	if gameWon {
+	if l.game.Won() {
 		l.gt.goToEndWin()
 	}
-	if gameLost {
+	if l.game.Lost() {
 		l.gt.goToEndFail()
 	}
 }
 
-// Tick handles user input, updating the current word and its completion status.
+// drawRace reports the player's progress to the room and shows everyone's on
+// the status line, moving on to the standings once the player is done.
+func (l *gameLevel) drawRace() {
+	lobby := l.gt.lobby
+	lobby.progress(l.racer, l.game.Result().PlayerWords, len(l.game.words), l.game.Lost(), time.Now())
+
+	ri := lobby.roomOf(l.racer)
+	var standings []string
+	for _, rc := range ri.standings() {
+		standings = append(standings, fmt.Sprintf("%s %d/%d", rc.name, rc.typed, rc.total))
+	}
+	l.statusText.SetText("** " + strings.Join(standings, " | ") + " **")
+
+	if l.game.Over() {
+		l.gt.goToRaceResults()
+	}
+}
+
+// step advances the simulation in fixed steps up to now, applying each input
+// in the step that covers it so the outcome does not depend on the frame rate.
+func (l *gameLevel) step(now time.Time) {
+	for !l.game.Over() && !l.lastStep.Add(stepSize).After(now) {
+		next := l.lastStep.Add(stepSize)
+		var due []Input
+		for len(l.inputs) > 0 && l.inputs[0].at.Before(next) {
+			due = append(due, l.inputs[0].in)
+			l.inputs = l.inputs[1:]
+		}
+		l.game.Step(stepSize, due)
+		l.lastStep = next
+	}
+}
+
+// drawWord renders a single word on the screen with the appropriate colors.
+func (l *gameLevel) drawWord(s *tl.Screen, w *word) {
+	fgComplete, fgTodo := tl.ColorRed, tl.ColorGreen
+	var bg tl.Attr
+	switch {
+	case w.Complete():
+		bg = tl.AttrUnderline
+	case w.startedBy == pc:
+		bg = tl.ColorBlue
+	case w.startedBy != 0:
+		bg = tl.ColorCyan
+	}
+	i := 0
+	for row, line := range w.lines {
+		x, y := w.x, w.y+row
+		for _, ch := range line {
+			if w.startedBy == 0 {
+				s.RenderCell(x, y, &tl.Cell{Fg: fgTodo, Bg: tl.ColorDefault, Ch: ch})
+			} else if i < w.completedChars {
+				s.RenderCell(x, y, &tl.Cell{Fg: fgComplete, Bg: bg, Ch: ch})
+			} else if wrong := i - w.completedChars; wrong < len(w.wrong) {
+				// Mistakes are shown in place of the characters that were due, if they fit
+				typed := w.wrong[wrong]
+				if runewidth.RuneWidth(typed) != runewidth.RuneWidth(ch) {
+					typed = ch
+				}
+				s.RenderCell(x, y, &tl.Cell{Fg: tl.ColorWhite, Bg: tl.ColorRed, Ch: typed})
+			} else {
+				s.RenderCell(x, y, &tl.Cell{Fg: fgTodo, Bg: bg, Ch: ch})
+			}
+			x += runewidth.RuneWidth(ch)
+			i++
+		}
+	}
+}
+
+// Tick queues user input to be applied to the current word in the step covering it.
 func (l *gameLevel) Tick(e tl.Event) {
+	if !l.gt.handleInput(e) {
+		return
+	}
 	if e.Type == tl.EventKey {
-		if l.currentWord != nil {
-			l.currentWord.KeyDown(e.Ch)
+		if l.paused {
+			l.tickPause(e)
+			return
+		}
+		// Races carry on for everyone else, so they can't be paused
+		if e.Key == tl.KeyEsc && l.racer == nil {
+			l.pause()
+			return
+		}
+		var in Input
+		switch {
+		case e.Key == tl.KeyBackspace || e.Key == tl.KeyBackspace2:
+			in.Action = Erase
+		case e.Key == tl.KeyTab:
+			in.Action = NextTarget
+		case e.Key == tl.KeySpace:
+			in.Ch = ' '
+		case e.Ch != 0:
+			in.Ch = e.Ch
+		default:
+			return
 		}
+		l.inputs = append(l.inputs, pendingInput{in: in, at: l.gt.clock.Now()})
 	}
 }
 
@@ -1,17 +1,11 @@
 module gopher_typer
 
-go 1.20
+go 1.21
 
 require (
-	github.com/JoelOtter/termloop v0.0.0-20210806173944-5f7c38744afb
-	github.com/gophergala2016/gopher_typer v0.0.0-20160125001054-26c7244a0b70
-	github.com/kr/pty v1.1.8
+	github.com/mattn/go-runewidth v0.0.9
+	github.com/nsf/termbox-go v1.1.1
 	golang.org/x/crypto v0.7.0
 )
 
-require (
-	github.com/creack/pty v1.1.18 // indirect
-	github.com/mattn/go-runewidth v0.0.9 // indirect
-	github.com/nsf/termbox-go v1.1.1 // indirect
-	golang.org/x/sys v0.6.0 // indirect
-)
+require golang.org/x/sys v0.6.0 // indirect
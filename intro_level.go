@@ -1,15 +1,19 @@
 package typeGopher
 
 import (
-	"os"
+	"fmt"
 	"time"
 
-	tl "github.com/JoelOtter/termloop"
+	"github.com/mattn/go-runewidth"
+	tl "gopher_typer/term"
 )
 
 type introLevel struct {
 	tl.Level
 	gt              *GopherTyper
+	fg              tl.Attr
+	bg              tl.Attr
+	currentPack     int
 	pressAKeyText   *tl.Text
 	needsRefresh    bool
 	swapMessageTime time.Time
@@ -24,6 +28,7 @@ func (l *introLevel) Activate() {
 
 // refresh updates the intro level's display, adding the necessary entities and text.
 func (l *introLevel) refresh() {
+	l.Level = tl.NewBaseLevel(tl.Cell{Bg: l.bg, Fg: l.fg})
 	l.gt.intro.AddEntity(&l.gt.console)
 	l.gt.console.SetText("")
 	w, h := l.gt.g.Screen().Size()
@@ -31,48 +36,91 @@ func (l *introLevel) refresh() {
 	rect := tl.NewRectangle(10, 2, w-20, h-4, tl.ColorCyan)
 	l.AddEntity(rect)
 
-	logo, _ := os.ReadFile("data/logo.txt")
+	logo, _ := readData("logo.txt")
 	c := tl.CanvasFromString(string(logo))
 	logoEntity := tl.NewEntityFromCanvas(w/2-len(c)/2, quarterH, tl.CanvasFromString(string(logo)))
 	l.AddEntity(logoEntity)
 
-	msg := "Press any key to continue"
+	msg := "Press Enter to start"
+	if sg := l.gt.saved; sg != nil {
+		msg = fmt.Sprintf("Press Enter to start or C to continue (level %d, $%d)", sg.Stats.LevelsCompleted+1, sg.Stats.Dollars)
+	}
 	l.pressAKeyText = tl.NewText(w/2-len(msg)/2, h/2, msg, tl.ColorBlue|tl.AttrReverse, tl.ColorDefault)
 	l.AddEntity(l.pressAKeyText)
 
-	instructions, _ := os.ReadFile("data/instructions.txt")
+	instructions, _ := readData("instructions.txt")
 	c = tl.CanvasFromString(string(instructions))
 	l.AddEntity(tl.NewEntityFromCanvas(w/2-len(c)/2, h/2+2, c))
 
+	if len(l.gt.packs) > 1 {
+		l.printPacks(w/2, h/2+5)
+	}
+
+	msg = fmt.Sprintf("Playing as %s. H for high scores", l.gt.playerName)
+	if l.gt.lobby != nil {
+		msg += ", R to race other players"
+	}
+	l.AddEntity(tl.NewText(w/2-runewidth.StringWidth(msg)/2, h-4, msg, tl.ColorBlack, tl.ColorDefault))
+
 	l.needsRefresh = false
 }
 
+// printPacks shows the chosen word pack and its description, centred on x.
+func (l *introLevel) printPacks(x, y int) {
+	p := l.gt.packs[l.currentPack]
+	msg := fmt.Sprintf("Word pack: < %s > (%d/%d, Up/Down to change)", p.name, l.currentPack+1, len(l.gt.packs))
+	l.AddEntity(tl.NewText(x-runewidth.StringWidth(msg)/2, y, msg, tl.ColorBlack, tl.ColorDefault))
+	l.AddEntity(tl.NewText(x-runewidth.StringWidth(p.description)/2, y+1, p.description, tl.ColorBlue, tl.ColorDefault))
+}
+
 // Draw refreshes the intro level's display if needed and updates the "Press any key" text's appearance.
 func (l *introLevel) Draw(screen *tl.Screen) {
 	if l.needsRefresh {
 		l.refresh()
 	}
-	if time.Now().After(l.swapMessageTime) {
+	if l.gt.clock.Now().After(l.swapMessageTime) {
 		if l.reverseText {
 			l.pressAKeyText.SetColor(tl.ColorBlue, tl.ColorDefault)
 		} else {
 			l.pressAKeyText.SetColor(tl.ColorBlue|tl.AttrReverse, tl.ColorDefault)
 		}
 		l.reverseText = !l.reverseText
-		l.swapMessageTime = time.Now().Add(500 * time.Millisecond)
+		l.swapMessageTime = l.gt.clock.Now().Add(500 * time.Millisecond)
 	}
 	l.Level.Draw(screen)
 }
 
-// Tick handles user input, transitioning to the game level when a key is pressed.
+// Tick handles user input, choosing a word pack and transitioning to the game level when Enter is pressed.
 func (l *introLevel) Tick(event tl.Event) {
+	if !l.gt.handleInput(event) {
+		return
+	}
 	if event.Type == tl.EventKey {
-		l.gt.goToGame()
+		if event.Key == tl.KeyArrowDown || event.Ch == 'j' {
+			l.currentPack = (l.currentPack + 1) % len(l.gt.packs)
+			l.needsRefresh = true
+		} else if event.Key == tl.KeyArrowUp || event.Ch == 'k' {
+			l.currentPack = (l.currentPack - 1)
+			if l.currentPack < 0 {
+				l.currentPack = len(l.gt.packs) - 1
+			}
+			l.needsRefresh = true
+		} else if event.Key == tl.KeyEnter {
+			l.gt.pack = l.gt.packs[l.currentPack]
+			l.gt.goToGame()
+		} else if (event.Ch == 'C' || event.Ch == 'c') && l.gt.saved != nil {
+			l.gt.continueGame()
+		} else if event.Ch == 'H' || event.Ch == 'h' {
+			l.gt.goToScores()
+		} else if (event.Ch == 'R' || event.Ch == 'r') && l.gt.lobby != nil {
+			l.gt.pack = l.gt.packs[l.currentPack]
+			l.gt.goToLobby()
+		}
 	}
 }
 
 // newIntroLevel creates a new intro level with the given GopherTyper, foreground, and background attributes.
 func newIntroLevel(g *GopherTyper, fg, bg tl.Attr) introLevel {
 	l := tl.NewBaseLevel(tl.Cell{Bg: bg, Fg: fg})
-	return introLevel{Level: l, gt: g}
+	return introLevel{Level: l, gt: g, fg: fg, bg: bg}
 }
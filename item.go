@@ -15,8 +15,9 @@ type item interface {
 	Reset(gt *GopherTyper)
 	Purchase(g *storeLevel) bool
 	Dupe() item
+	save() savedItem
 
-	Tick(g *gameLevel)
+	Tick(g *Game)
 }
 
 type goroutineItem struct {
@@ -49,36 +50,40 @@ func (i *goroutineItem) PriceDesc() string {
 	return fmt.Sprintf("$%d", i.Price())
 }
 
-// Tick handles the logic for the goroutineItem during each game tick.
-func (i *goroutineItem) Tick(gl *gameLevel) {
-	if time.Now().After(i.wakeAt) {
+// Tick handles the logic for the goroutineItem during each game step.
+func (i *goroutineItem) Tick(g *Game) {
+	if i.wakeAt.IsZero() {
+		i.sleep(g.now, g.rng)
+		return
+	}
+	if g.now.After(i.wakeAt) {
 		if i.currentWord == nil {
 			var possibleWords []int
-			for i, w := range gl.words {
-				if gl.currentWord != gl.words[i] && !w.Complete() && w.startedBy == 0 {
+			for i, w := range g.words {
+				if g.currentWord != g.words[i] && !w.Complete() && w.startedBy == 0 {
 					possibleWords = append(possibleWords, i)
 				}
 			}
 			if len(possibleWords) > 0 {
-				i.currentWord = gl.words[possibleWords[rand.Intn(len(possibleWords))]]
+				i.currentWord = g.words[possibleWords[g.rng.Intn(len(possibleWords))]]
 				i.currentWord.completedChars++
 				i.currentWord.startedBy = i.id
 			}
 		} else {
 			i.currentWord.completedChars++
-			gl.gt.stats.Garbage++
+			g.stats.Garbage++
 			if i.currentWord.Complete() {
 				i.currentWord = nil
 			}
 		}
 
-		i.sleep()
+		i.sleep(g.now, g.rng)
 	}
 }
 
-// sleep sets the wakeAt time for the goroutineItem.
-func (i *goroutineItem) sleep() {
-	i.wakeAt = time.Now().Add(i.baseWait/time.Duration(i.cpuUpgrades) + time.Duration(rand.Intn(int(i.waitRange))))
+// sleep sets the wakeAt time for the goroutineItem relative to now.
+func (i *goroutineItem) sleep(now time.Time, rng *rand.Rand) {
+	i.wakeAt = now.Add(i.baseWait/time.Duration(i.cpuUpgrades) + time.Duration(rng.Intn(int(i.waitRange))))
 }
 
 // SetID sets the ID for the goroutineItem.
@@ -89,6 +94,7 @@ func (i *goroutineItem) SetID(id int) {
 // Reset resets the state of the goroutineItem.
 func (i *goroutineItem) Reset(gt *GopherTyper) {
 	i.currentWord = nil
+	i.wakeAt = time.Time{}
 	i.cpuUpgrades = gt.stats.CPUUpgrades
 	i.price = 1000
 	for _, itm := range gt.items {
@@ -105,6 +111,11 @@ func (i *goroutineItem) Dupe() item {
 	return &dupe
 }
 
+// save returns the goroutineItem's state for a save file.
+func (i *goroutineItem) save() savedItem {
+	return savedItem{Name: i.Name(), ID: i.id, Price: i.price, BaseWait: i.baseWait, WaitRange: i.waitRange, CPUUpgrades: i.cpuUpgrades}
+}
+
 // Purchase handles the purchasing logic for the goroutine item and returns true if the purchase is successful.
 func (i *goroutineItem) Purchase(l *storeLevel) bool {
 	return true
@@ -113,7 +124,6 @@ func (i *goroutineItem) Purchase(l *storeLevel) bool {
 // newGoroutineItem creates a new goroutine item.
 func newGoroutineItem(waitRange, baseWait time.Duration) *goroutineItem {
 	item := goroutineItem{waitRange: waitRange, baseWait: baseWait, cpuUpgrades: 1}
-	item.sleep()
 	return &item
 }
 
@@ -142,8 +152,8 @@ func (i *cpuUpgradeItem) PriceDesc() string {
 	return fmt.Sprintf("$%d", i.Price())
 }
 
-// Tick handles the logic for the cpuUpgradeItem during each game tick.
-func (i *cpuUpgradeItem) Tick(gl *gameLevel) {
+// Tick handles the logic for the cpuUpgradeItem during each game step.
+func (i *cpuUpgradeItem) Tick(g *Game) {
 }
 
 // SetID sets the ID for the cpuUpgradeItem.
@@ -162,6 +172,11 @@ func (i *cpuUpgradeItem) Purchase(l *storeLevel) bool {
 	return false
 }
 
+// save returns the cpuUpgradeItem's state for a save file.
+func (i *cpuUpgradeItem) save() savedItem {
+	return savedItem{Name: i.Name(), ID: i.id, Price: i.price}
+}
+
 // Dupe creates a duplicate of the cpuUpgradeItem.
 func (i *cpuUpgradeItem) Dupe() item {
 	var dupe cpuUpgradeItem
@@ -195,8 +210,8 @@ func (i *goUpgradeItem) PriceDesc() string {
 	return fmt.Sprintf("$%d", i.Price())
 }
 
-// Tick handles the logic for the goUpgradeItem during each game tick.
-func (i *goUpgradeItem) Tick(gl *gameLevel) {
+// Tick handles the logic for the goUpgradeItem during each game step.
+func (i *goUpgradeItem) Tick(g *Game) {
 }
 
 // SetID sets the ID for the goUpgradeItem.
@@ -216,6 +231,11 @@ func (i *goUpgradeItem) Purchase(l *storeLevel) bool {
 	return false
 }
 
+// save returns the goUpgradeItem's state for a save file.
+func (i *goUpgradeItem) save() savedItem {
+	return savedItem{Name: i.Name(), ID: i.id, Price: i.price}
+}
+
 // Dupe creates a duplicate of the goUpgradeItem.
 func (i *goUpgradeItem) Dupe() item {
 	var dupe goUpgradeItem
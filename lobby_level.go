@@ -0,0 +1,178 @@
+package typeGopher
+
+import (
+	"fmt"
+	"time"
+
+	tl "gopher_typer/term"
+)
+
+// lobbyLevel lists the race rooms, waits in a room for its race to start and
+// shows the standings once the player's race is over.
+type lobbyLevel struct {
+	tl.Level
+	gt           *GopherTyper
+	fg           tl.Attr
+	bg           tl.Attr
+	rooms        []roomInfo
+	currentRoom  int
+	seen         int
+	needsRefresh bool
+	finished     bool
+}
+
+// Activate sets the lobby as the current level and marks it for refresh.
+func (l *lobbyLevel) Activate() {
+	l.gt.console.SetText("")
+	l.needsRefresh = true
+	l.gt.g.Screen().SetLevel(l)
+}
+
+// refresh redraws the lobby from the latest state of the rooms.
+func (l *lobbyLevel) refresh() {
+	l.Level = tl.NewBaseLevel(tl.Cell{Bg: l.bg, Fg: l.fg})
+	l.AddEntity(&l.gt.console)
+	w, h := l.gt.g.Screen().Size()
+	l.AddEntity(tl.NewRectangle(10, 2, w-20, h-4, tl.ColorCyan))
+
+	switch {
+	case l.gt.racer == nil:
+		l.printRooms(w, h)
+	case l.finished:
+		l.printStandings(w, h, "RACE RESULTS", "Press Enter to return to the lobby")
+	default:
+		ri := l.gt.lobby.roomOf(l.gt.racer)
+		msg := fmt.Sprintf("Waiting for %s to start the race, Esc to leave", ri.host)
+		if ri.host == l.gt.racer.name {
+			msg = "Press Enter to start the race, Esc to leave"
+		}
+		l.printStandings(w, h, ri.name, msg)
+	}
+	l.needsRefresh = false
+}
+
+// printRooms lists the rooms that can be joined, marking the chosen one.
+func (l *lobbyLevel) printRooms(w, h int) {
+	l.rooms = l.gt.lobby.list()
+	if l.currentRoom >= len(l.rooms) {
+		l.currentRoom = len(l.rooms) - 1
+	}
+	if l.currentRoom < 0 {
+		l.currentRoom = 0
+	}
+
+	msg := "RACE LOBBY"
+	l.AddEntity(tl.NewText(w/2-len(msg)/2, 4, msg, tl.ColorBlue|tl.AttrReverse, tl.ColorDefault))
+
+	y := 6
+	for i, ri := range l.rooms {
+		if y >= h-6 {
+			break
+		}
+		state := "waiting"
+		if !ri.started.IsZero() {
+			state = "racing"
+		}
+		msg = fmt.Sprintf("  %-24.24s %-12.12s %d racers, %s", ri.name, ri.pack, len(ri.racers), state)
+		fg := tl.ColorBlack
+		if i == l.currentRoom {
+			msg = ">" + msg[1:]
+			fg = tl.ColorBlue
+		}
+		l.AddEntity(tl.NewText(14, y, msg, fg, tl.ColorDefault))
+		y++
+	}
+	if len(l.rooms) == 0 {
+		msg = "No rooms yet"
+		l.AddEntity(tl.NewText(w/2-len(msg)/2, y+1, msg, tl.ColorBlack, tl.ColorDefault))
+	}
+
+	msg = "Up/Down(j/k), Enter to join, N for a new room, Esc to go back"
+	l.AddEntity(tl.NewText(w/2-len(msg)/2, h-4, msg, tl.ColorBlack, tl.ColorDefault))
+}
+
+// printStandings lists the racers in the player's room, in race order.
+func (l *lobbyLevel) printStandings(w, h int, title, prompt string) {
+	ri := l.gt.lobby.roomOf(l.gt.racer)
+	l.AddEntity(tl.NewText(w/2-len(title)/2, 4, title, tl.ColorBlue|tl.AttrReverse, tl.ColorDefault))
+	msg := fmt.Sprintf("Word pack: %s", ri.pack)
+	l.AddEntity(tl.NewText(w/2-len(msg)/2, 5, msg, tl.ColorBlack, tl.ColorDefault))
+
+	y := 7
+	for i, rc := range ri.standings() {
+		if y >= h-6 {
+			break
+		}
+		msg = fmt.Sprintf("%-4d %-16.16s %3d/%-3d %s", i+1, rc.name, rc.typed, rc.total, rc.status(ri.started))
+		fg := tl.ColorBlack
+		if rc.name == l.gt.racer.name {
+			fg = tl.ColorBlue
+		}
+		l.AddEntity(tl.NewText(14, y, msg, fg, tl.ColorDefault))
+		y++
+	}
+
+	l.AddEntity(tl.NewText(w/2-len(prompt)/2, h-4, prompt, tl.ColorBlack, tl.ColorDefault))
+}
+
+// Draw refreshes the lobby whenever any room has changed, and starts the
+// player's race as soon as its host does.
+func (l *lobbyLevel) Draw(screen *tl.Screen) {
+	if changed, v := l.gt.lobby.changed(l.seen); changed || l.needsRefresh {
+		l.seen = v
+		l.refresh()
+	}
+	if l.gt.racer != nil && !l.finished && !l.gt.lobby.roomOf(l.gt.racer).started.IsZero() {
+		l.gt.goToRace()
+		return
+	}
+	l.Level.Draw(screen)
+}
+
+// Tick handles choosing, joining, creating and leaving rooms.
+func (l *lobbyLevel) Tick(event tl.Event) {
+	if !l.gt.handleInput(event) {
+		return
+	}
+	if event.Type != tl.EventKey {
+		return
+	}
+	l.needsRefresh = true
+
+	if l.gt.racer != nil {
+		if event.Key == tl.KeyEsc || l.finished && event.Key == tl.KeyEnter {
+			l.gt.leaveRace()
+			l.finished = false
+		} else if event.Key == tl.KeyEnter && !l.finished {
+			if err := l.gt.lobby.start(l.gt.racer, time.Now()); err != nil {
+				l.gt.console.SetText(fmt.Sprintf("Err: %+v", err))
+			}
+		}
+		return
+	}
+
+	if event.Key == tl.KeyArrowDown || event.Ch == 'j' {
+		l.currentRoom++
+	} else if event.Key == tl.KeyArrowUp || event.Ch == 'k' {
+		l.currentRoom--
+	} else if event.Key == tl.KeyEnter && len(l.rooms) > 0 {
+		rc, err := l.gt.lobby.join(l.rooms[l.currentRoom].id, l.gt.playerName)
+		if err != nil {
+			l.gt.console.SetText(fmt.Sprintf("Err: %+v", err))
+			return
+		}
+		l.gt.console.SetText("")
+		l.gt.racer = rc
+	} else if event.Ch == 'N' || event.Ch == 'n' {
+		l.gt.console.SetText("")
+		l.gt.racer = l.gt.lobby.create(l.gt.playerName, l.gt.pack, l.gt.difficulty, l.gt.rng.Int63())
+	} else if event.Key == tl.KeyEsc {
+		l.gt.goToIntro()
+	}
+}
+
+// newLobbyLevel creates a new race lobby level with the given GopherTyper, foreground, and background attributes.
+func newLobbyLevel(g *GopherTyper, fg, bg tl.Attr) lobbyLevel {
+	l := tl.NewBaseLevel(tl.Cell{Bg: bg, Fg: fg})
+	return lobbyLevel{Level: l, gt: g, fg: fg, bg: bg}
+}
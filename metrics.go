@@ -0,0 +1,137 @@
+package typeGopher
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// Metrics collects detailed statistics about the player's typing over a run,
+// and is what the end screen, the high score table and --metrics all report.
+type Metrics struct {
+	Started    time.Time               `json:"started"`
+	Keystrokes int                     `json:"keystrokes"`
+	Errors     int                     `json:"errors"`
+	TypingTime time.Duration           `json:"typing_time"`
+	Chars      map[string]*CharMetrics `json:"chars"`
+	Words      []WordMetrics           `json:"words"`
+}
+
+// CharMetrics counts how often a character was due and how often it was mistyped.
+type CharMetrics struct {
+	Typed  int `json:"typed"`
+	Errors int `json:"errors"`
+}
+
+// WordMetrics describes how the player typed a single word.
+type WordMetrics struct {
+	Word string `json:"word"`
+	// Reaction is the time from the player being free to pick a new word to
+	// the first keystroke of this one.
+	Reaction time.Duration `json:"reaction"`
+	// Duration is the time from that same point to the word's completion.
+	Duration time.Duration `json:"duration"`
+	Errors   int           `json:"errors"`
+}
+
+// newMetrics creates an empty collector for a session starting at the given time.
+func newMetrics(started time.Time) *Metrics {
+	return &Metrics{Started: started, Chars: map[string]*CharMetrics{}}
+}
+
+// keystroke records a key press made when expected was the next character due.
+func (m *Metrics) keystroke(expected rune, correct bool) {
+	c, ok := m.Chars[string(expected)]
+	if !ok {
+		c = &CharMetrics{}
+		m.Chars[string(expected)] = c
+	}
+	m.Keystrokes++
+	c.Typed++
+	if !correct {
+		m.Errors++
+		c.Errors++
+	}
+}
+
+// word records a word the player finished.
+func (m *Metrics) word(w WordMetrics) {
+	m.Words = append(m.Words, w)
+}
+
+// level adds the length of a finished level to the time spent typing.
+func (m *Metrics) level(d time.Duration) {
+	m.TypingTime += d
+}
+
+// WPM returns the typing speed in words per minute, counting five correct keystrokes as a word.
+func (m *Metrics) WPM() float64 {
+	if m.TypingTime <= 0 {
+		return 0
+	}
+	return float64(m.Keystrokes-m.Errors) / 5 / m.TypingTime.Minutes()
+}
+
+// Accuracy returns how much of the run's typing was correct.
+func (m *Metrics) Accuracy() float64 {
+	return accuracy(m.Keystrokes, m.Errors)
+}
+
+// accuracy returns the fraction of keystrokes that were not errors, or 0 if there were none.
+func accuracy(keystrokes, errors int) float64 {
+	if keystrokes == 0 {
+		return 0
+	}
+	return float64(keystrokes-errors) / float64(keystrokes)
+}
+
+// clone returns a copy of m that shares nothing with it.
+func (m *Metrics) clone() *Metrics {
+	c := *m
+	c.Chars = make(map[string]*CharMetrics, len(m.Chars))
+	for ch, cm := range m.Chars {
+		cp := *cm
+		c.Chars[ch] = &cp
+	}
+	c.Words = append([]WordMetrics(nil), m.Words...)
+	return &c
+}
+
+// AverageReaction returns the mean time taken to start typing a targeted word.
+func (m *Metrics) AverageReaction() time.Duration {
+	if len(m.Words) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, w := range m.Words {
+		total += w.Reaction
+	}
+	return total / time.Duration(len(m.Words))
+}
+
+// MostMissed returns up to n characters with the most errors, worst first.
+func (m *Metrics) MostMissed(n int) []string {
+	var chars []string
+	for ch, c := range m.Chars {
+		if c.Errors > 0 {
+			chars = append(chars, ch)
+		}
+	}
+	sort.Slice(chars, func(i, j int) bool {
+		ei, ej := m.Chars[chars[i]].Errors, m.Chars[chars[j]].Errors
+		if ei != ej {
+			return ei > ej
+		}
+		return chars[i] < chars[j]
+	})
+	if len(chars) > n {
+		chars = chars[:n]
+	}
+	return chars
+}
+
+// WriteJSON writes the metrics to w as a single line of JSON, so sessions can be appended to one file.
+func (m *Metrics) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(m)
+}
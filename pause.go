@@ -0,0 +1,125 @@
+package typeGopher
+
+import (
+	"fmt"
+
+	tl "gopher_typer/term"
+)
+
+// The choices on the pause menu, in the order they are listed.
+const (
+	pauseResume = iota
+	pauseRestart
+	pauseStore
+	pauseSpeed
+	pauseQuit
+	pauseChoices
+)
+
+// speeds are the game speeds the player can pick from the pause menu.
+var speeds = []float64{0.5, 0.75, 1}
+
+// pause stops game time, freezing the words, goroutines and garbage
+// collection, and opens the pause menu.
+func (l *gameLevel) pause() {
+	l.gt.clock.Pause()
+	l.paused = true
+	l.pauseChoice = pauseResume
+}
+
+// resume closes the pause menu and starts game time again from where it stopped.
+func (l *gameLevel) resume() {
+	l.paused = false
+	l.gt.clock.Resume()
+}
+
+// restart abandons the level being played, putting the player's stats back
+// to how they were when it began.
+func (l *gameLevel) restart() {
+	l.resume()
+	l.abandon()
+	l.gt.stats = l.startStats
+}
+
+// abandon counts the time spent on a level left unfinished as time spent
+// typing, since its keystrokes are already in the metrics.
+func (l *gameLevel) abandon() {
+	l.gt.metrics.level(l.game.Result().Duration)
+}
+
+// tickPause handles the keys pressed while the pause menu is open.
+func (l *gameLevel) tickPause(e tl.Event) {
+	if e.Type != tl.EventKey {
+		return
+	}
+	switch {
+	case e.Key == tl.KeyEsc:
+		l.resume()
+	case e.Key == tl.KeyArrowDown || e.Ch == 'j':
+		l.pauseChoice = (l.pauseChoice + 1) % pauseChoices
+	case e.Key == tl.KeyArrowUp || e.Ch == 'k':
+		l.pauseChoice = (l.pauseChoice + pauseChoices - 1) % pauseChoices
+	case l.pauseChoice == pauseSpeed && (e.Key == tl.KeyArrowLeft || e.Key == tl.KeyArrowRight):
+		i := speedIndex(l.gt.speed)
+		if e.Key == tl.KeyArrowLeft && i > 0 {
+			i--
+		} else if e.Key == tl.KeyArrowRight && i < len(speeds)-1 {
+			i++
+		}
+		l.gt.setSpeed(speeds[i])
+	case e.Key == tl.KeyEnter:
+		switch l.pauseChoice {
+		case pauseResume:
+			l.resume()
+		case pauseRestart:
+			l.restart()
+			l.gt.goToGame()
+		case pauseStore:
+			l.restart()
+			l.gt.goToStore()
+		case pauseQuit:
+			l.abandon()
+			l.gt.g.Stop()
+		}
+	}
+}
+
+// drawPause draws the pause menu over the frozen level.
+func (l *gameLevel) drawPause(s *tl.Screen) {
+	lines := []string{
+		"Resume",
+		"Restart level",
+		"Go to the store",
+		fmt.Sprintf("Speed: < %.0f%% >", l.gt.speed*100),
+		"Quit",
+	}
+	w, h := s.Size()
+	bw, bh := 44, len(lines)+8
+	x, y := w/2-bw/2, h/2-bh/2
+	tl.NewRectangle(x, y, bw, bh, tl.ColorCyan).Draw(s)
+
+	msg := "PAUSED"
+	tl.NewText(w/2-len(msg)/2, y+1, msg, tl.ColorBlue|tl.AttrReverse, tl.ColorDefault).Draw(s)
+	for i, line := range lines {
+		fg, bg := tl.ColorBlack, tl.ColorCyan
+		if i == l.pauseChoice {
+			fg, bg = tl.ColorWhite, tl.ColorBlue
+		}
+		tl.NewText(w/2-len(line)/2, y+3+i, line, fg, bg).Draw(s)
+	}
+	msg = "Slower speeds don't count for high scores"
+	if l.pauseChoice != pauseSpeed {
+		msg = "Up/Down to choose, Enter to pick, Esc to resume"
+	}
+	tl.NewText(w/2-len(msg)/2, y+bh-2, msg, tl.ColorBlack, tl.ColorCyan).Draw(s)
+}
+
+// speedIndex returns where speed is in speeds, or the last, full speed if it isn't there.
+func speedIndex(speed float64) int {
+	for i, s := range speeds {
+		if s == speed {
+			return i
+		}
+	}
+	return len(speeds) - 1
+}
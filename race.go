@@ -0,0 +1,236 @@
+package typeGopher
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// raceLevel is the level whose difficulty races are played at.
+	raceLevel = 9
+	// raceWidth and raceHeight are the size of the field every racer plays on,
+	// so that all of them get the same layout whatever their terminal size.
+	raceWidth  = 80
+	raceHeight = 24
+)
+
+// Lobby holds the race rooms shared by every game connected to it, such as
+// all the sessions on one server. It is safe for concurrent use.
+type Lobby struct {
+	mu      sync.Mutex
+	rooms   []*room
+	nextID  int
+	version int
+}
+
+// NewLobby creates an empty lobby.
+func NewLobby() *Lobby {
+	return &Lobby{}
+}
+
+// room is a group of racers who play the same seeded layout of words.
+// Everything but racers and startedAt is fixed when the room is made.
+type room struct {
+	id         int
+	name       string
+	seed       int64
+	pack       *wordPack
+	difficulty difficulty
+	racers     []*racer
+	startedAt  time.Time
+}
+
+// racer is one player's place in a room.
+type racer struct {
+	room       *room
+	name       string
+	typed      int
+	total      int
+	finishedAt time.Time
+	out        bool
+	left       bool
+}
+
+// roomInfo is a snapshot of a room, safe to read without the lobby's lock.
+type roomInfo struct {
+	id      int
+	name    string
+	pack    string
+	host    string
+	started time.Time
+	racers  []racer
+}
+
+// changed reports whether anything in the lobby has changed since version seen,
+// and returns the current version.
+func (lb *Lobby) changed(seen int) (bool, int) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return lb.version != seen, lb.version
+}
+
+// list returns a snapshot of every room.
+func (lb *Lobby) list() []roomInfo {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	var rooms []roomInfo
+	for _, r := range lb.rooms {
+		rooms = append(rooms, r.info())
+	}
+	return rooms
+}
+
+// info returns a snapshot of the room. The lobby's lock must be held.
+func (r *room) info() roomInfo {
+	ri := roomInfo{id: r.id, name: r.name, pack: r.pack.name, started: r.startedAt}
+	for _, rc := range r.racers {
+		if !rc.left {
+			if ri.host == "" {
+				ri.host = rc.name
+			}
+			ri.racers = append(ri.racers, *rc)
+		}
+	}
+	return ri
+}
+
+// roomOf returns a snapshot of the room rc is in.
+func (lb *Lobby) roomOf(rc *racer) roomInfo {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return rc.room.info()
+}
+
+// create opens a new room with name as its host, racing with pack and d.
+func (lb *Lobby) create(name string, pack *wordPack, d difficulty, seed int64) *racer {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.nextID++
+	r := &room{id: lb.nextID, name: fmt.Sprintf("%s's room", name), seed: seed, pack: pack, difficulty: d}
+	rc := &racer{room: r, name: name}
+	r.racers = append(r.racers, rc)
+	lb.rooms = append(lb.rooms, r)
+	lb.version++
+	return rc
+}
+
+// join adds name to the room with the given id, which must still be waiting to start.
+func (lb *Lobby) join(id int, name string) (*racer, error) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	for _, r := range lb.rooms {
+		if r.id != id {
+			continue
+		}
+		if !r.startedAt.IsZero() {
+			return nil, errors.New("that race has already started")
+		}
+		rc := &racer{room: r, name: name}
+		r.racers = append(r.racers, rc)
+		lb.version++
+		return rc, nil
+	}
+	return nil, errors.New("that room has closed")
+}
+
+// leave takes rc out of its room, closing the room once everyone has left.
+func (lb *Lobby) leave(rc *racer) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	r := rc.room
+	rc.left = true
+	if r.startedAt.IsZero() {
+		for i, other := range r.racers {
+			if other == rc {
+				r.racers = append(r.racers[:i], r.racers[i+1:]...)
+				break
+			}
+		}
+	}
+	empty := true
+	for _, other := range r.racers {
+		if !other.left {
+			empty = false
+		}
+	}
+	if empty {
+		for i, other := range lb.rooms {
+			if other == r {
+				lb.rooms = append(lb.rooms[:i], lb.rooms[i+1:]...)
+				break
+			}
+		}
+	}
+	lb.version++
+}
+
+// start begins the race in rc's room. Only the host may start it.
+func (lb *Lobby) start(rc *racer, now time.Time) error {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	r := rc.room
+	if len(r.racers) == 0 || r.racers[0] != rc {
+		return errors.New("only the host can start the race")
+	}
+	if r.startedAt.IsZero() {
+		r.startedAt = now
+		lb.version++
+	}
+	return nil
+}
+
+// progress records how far rc has got through the race.
+func (lb *Lobby) progress(rc *racer, typed, total int, out bool, now time.Time) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	if rc.typed == typed && rc.total == total && rc.out == out {
+		return
+	}
+	rc.typed, rc.total, rc.out = typed, total, out
+	if typed == total && rc.finishedAt.IsZero() {
+		rc.finishedAt = now
+	}
+	lb.version++
+}
+
+// newGame lays out the room's race, which is the same for every racer in it.
+func (r *room) newGame(m *Metrics, start time.Time) *Game {
+	s := newStats()
+	s.LevelsCompleted = raceLevel
+	return newGame(&s, nil, r.pack, &r.difficulty, rand.New(rand.NewSource(r.seed)), m, raceWidth, raceHeight, start)
+}
+
+// standings returns the room's racers in race order: finishers by time, then
+// everyone else by how many words they have typed.
+func (ri roomInfo) standings() []racer {
+	racers := append([]racer(nil), ri.racers...)
+	sort.SliceStable(racers, func(i, j int) bool {
+		a, b := racers[i], racers[j]
+		if !a.finishedAt.IsZero() || !b.finishedAt.IsZero() {
+			if a.finishedAt.IsZero() || b.finishedAt.IsZero() {
+				return !a.finishedAt.IsZero()
+			}
+			return a.finishedAt.Before(b.finishedAt)
+		}
+		return a.typed > b.typed
+	})
+	return racers
+}
+
+// status describes how a racer is doing for the standings.
+func (rc racer) status(started time.Time) string {
+	switch {
+	case !rc.finishedAt.IsZero():
+		return fmt.Sprintf("finished in %.1fs", rc.finishedAt.Sub(started).Seconds())
+	case rc.out:
+		return "out"
+	case started.IsZero():
+		return "ready"
+	default:
+		return "racing"
+	}
+}
@@ -0,0 +1,143 @@
+package typeGopher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	tl "gopher_typer/term"
+)
+
+// replayVersion is bumped whenever the replay file format, or how the
+// recorded keys play out, changes.
+const replayVersion = 5
+
+// replayHeader is the first line of a replay file.
+type replayHeader struct {
+	Version    int         `json:"version"`
+	Seed       int64       `json:"seed"`
+	Difficulty *difficulty `json:"difficulty,omitempty"`
+	// Saving is set if the session kept a save file, and Save is what was in
+	// it when the session began, so Continue plays back the same game.
+	Saving bool      `json:"saving,omitempty"`
+	Save   *saveGame `json:"save,omitempty"`
+}
+
+// replayEvent is a key press, or a change of screen size, at a point in time.
+// Times are read from the clock under the game's speed and pauses, which
+// replaying the keys that changed them brings back.
+type replayEvent struct {
+	T      time.Duration `json:"t"`
+	Key    tl.Key        `json:"key,omitempty"`
+	Ch     rune          `json:"ch,omitempty"`
+	Width  int           `json:"w,omitempty"`
+	Height int           `json:"h,omitempty"`
+}
+
+// replayRecorder writes the key events of a session as JSON lines.
+type replayRecorder struct {
+	enc           *json.Encoder
+	began         time.Time
+	width, height int
+}
+
+// start writes the replay header and marks the beginning of the session.
+func (r *replayRecorder) start(gt *GopherTyper) error {
+	r.began = gt.clock.base.Now()
+	d := gt.difficulty
+	return r.enc.Encode(replayHeader{Version: replayVersion, Seed: gt.seed, Difficulty: &d, Saving: gt.savePath != "", Save: gt.saved})
+}
+
+// record writes a key event, preceded by the screen size whenever it has changed.
+func (r *replayRecorder) record(gt *GopherTyper, e tl.Event) {
+	t := gt.clock.base.Now().Sub(r.began)
+	if w, h := gt.g.Screen().Size(); w != r.width || h != r.height {
+		r.width, r.height = w, h
+		if err := r.enc.Encode(replayEvent{T: t, Width: w, Height: h}); err != nil {
+			gt.console.SetText(fmt.Sprintf("Err: %+v", err))
+		}
+	}
+	if err := r.enc.Encode(replayEvent{T: t, Key: e.Key, Ch: e.Ch}); err != nil {
+		gt.console.SetText(fmt.Sprintf("Err: %+v", err))
+	}
+}
+
+// Replay plays a recorded session back through a GopherTyper. Runs recorded
+// without a seed (see WithSeed) will not play back faithfully.
+type Replay struct {
+	header        replayHeader
+	events        []replayEvent
+	next          int
+	gt            *GopherTyper
+	clock         *FakeClock
+	began         time.Time
+	delivering    bool
+	width, height int
+}
+
+// NewReplay reads a replay file written by a session started WithRecording.
+func NewReplay(r io.Reader) (*Replay, error) {
+	dec := json.NewDecoder(r)
+	rp := Replay{}
+	if err := dec.Decode(&rp.header); err != nil {
+		return nil, fmt.Errorf("reading replay header: %w", err)
+	}
+	if rp.header.Version != replayVersion {
+		return nil, fmt.Errorf("unsupported replay version %d", rp.header.Version)
+	}
+	for {
+		var ev replayEvent
+		err := dec.Decode(&ev)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading replay event %d: %w", len(rp.events), err)
+		}
+		rp.events = append(rp.events, ev)
+	}
+	return &rp, nil
+}
+
+// start attaches the replay to the game it will drive.
+func (r *Replay) start(gt *GopherTyper) {
+	r.gt = gt
+	r.began = r.clock.Now()
+}
+
+// Draw moves the replay clock forward by the frame time, delivering every
+// recorded event that falls inside the frame at its exact game time.
+func (r *Replay) Draw(s *tl.Screen) {
+	target := r.clock.Now().Add(time.Duration(s.TimeDelta() * float64(time.Second)))
+
+	r.delivering = true
+	for r.next < len(r.events) {
+		ev := r.events[r.next]
+		at := r.began.Add(ev.T)
+		if at.After(target) {
+			break
+		}
+		r.clock.Set(at)
+		if ev.Width > 0 {
+			r.width, r.height = ev.Width, ev.Height
+		} else {
+			r.gt.level.Tick(tl.Event{Type: tl.EventKey, Key: ev.Key, Ch: ev.Ch})
+		}
+		r.next++
+	}
+	r.delivering = false
+	r.clock.Set(target)
+
+	msg := "REPLAY"
+	if r.next == len(r.events) {
+		msg = "REPLAY FINISHED"
+	}
+	w, _ := s.Size()
+	tl.NewText(w-len(msg), 0, msg, tl.ColorBlack, tl.ColorYellow).Draw(s)
+}
+
+// Tick ignores live input; the replay only responds to recorded events.
+func (r *Replay) Tick(e tl.Event) {
+}
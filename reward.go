@@ -0,0 +1,43 @@
+package typeGopher
+
+import "time"
+
+// bonusTime is how much time to spare on the closest call earns the full time bonus.
+const bonusTime = 10 * time.Second
+
+// reward is the money paid for winning a level, broken down by what earned it.
+// Each bonus is worth up to half of the level reward.
+type reward struct {
+	level    int
+	accuracy int
+	time     int
+	typing   int
+	result   Result
+}
+
+// total returns the whole reward.
+func (r reward) total() int {
+	return r.level + r.accuracy + r.time + r.typing
+}
+
+// calculateReward works out the reward for winning the given level with result r.
+func calculateReward(d *difficulty, level int, r Result) reward {
+	base := d.reward(level)
+	half := float64(base) / 2
+	rw := reward{level: base, result: r}
+
+	rw.accuracy = int(half * r.Accuracy())
+
+	spare := r.ClosestCall
+	if spare > bonusTime {
+		spare = bonusTime
+	}
+	if spare > 0 {
+		rw.time = int(half * float64(spare) / float64(bonusTime))
+	}
+
+	if words := r.PlayerWords + r.GoroutineWords; words > 0 {
+		rw.typing = int(half * float64(r.PlayerWords) / float64(words))
+	}
+	return rw
+}
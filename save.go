@@ -0,0 +1,154 @@
+package typeGopher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// saveVersion is bumped whenever the save file format changes.
+const saveVersion = 1
+
+// saveGame is everything needed to pick a game back up where it was left.
+type saveGame struct {
+	Version    int         `json:"version"`
+	SavedAt    time.Time   `json:"saved_at"`
+	Stats      stats       `json:"stats"`
+	Items      []savedItem `json:"items"`
+	Pack       string      `json:"pack"`
+	Difficulty difficulty  `json:"difficulty"`
+	// Metrics is the run's typing so far, so its WPM carries on after Continue.
+	Metrics *Metrics `json:"metrics,omitempty"`
+}
+
+// savedItem is an owned item as written to a save file.
+type savedItem struct {
+	Name        string        `json:"name"`
+	ID          int           `json:"id"`
+	Price       int           `json:"price"`
+	BaseWait    time.Duration `json:"base_wait,omitempty"`
+	WaitRange   time.Duration `json:"wait_range,omitempty"`
+	CPUUpgrades int           `json:"cpu_upgrades,omitempty"`
+}
+
+// defaultSavePath returns where the game is saved when no path is given.
+func defaultSavePath() (string, error) {
+	return configFilePath("save.json")
+}
+
+// readSave reads the save file at path, returning nil if there isn't one.
+func readSave(path string) (*saveGame, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sg saveGame
+	if err := json.Unmarshal(data, &sg); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if sg.Version != saveVersion {
+		return nil, fmt.Errorf("reading %s: unsupported save version %d", path, sg.Version)
+	}
+	return &sg, nil
+}
+
+// writeSave writes sg to path, replacing the old save only once the new one is complete.
+func writeSave(path string, sg *saveGame) error {
+	data, err := json.MarshalIndent(sg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// loadItem recreates an owned item from its saved form.
+func loadItem(s savedItem) (item, error) {
+	var i item
+	switch s.Name {
+	case (&goroutineItem{}).Name():
+		i = &goroutineItem{baseWait: s.BaseWait, waitRange: s.WaitRange, cpuUpgrades: s.CPUUpgrades, price: s.Price}
+	case (&cpuUpgradeItem{}).Name():
+		i = &cpuUpgradeItem{price: s.Price}
+	case (&goUpgradeItem{}).Name():
+		i = &goUpgradeItem{price: s.Price}
+	default:
+		return nil, fmt.Errorf("unknown item %q", s.Name)
+	}
+	i.SetID(s.ID)
+	return i, nil
+}
+
+// save captures the current game so it can be written to a save file.
+func (gt *GopherTyper) save() *saveGame {
+	sg := saveGame{
+		Version:    saveVersion,
+		SavedAt:    time.Now(),
+		Stats:      gt.stats,
+		Pack:       gt.pack.name,
+		Difficulty: gt.difficulty,
+		Metrics:    gt.metrics.clone(),
+	}
+	for _, i := range gt.items {
+		sg.Items = append(sg.Items, i.save())
+	}
+	return &sg
+}
+
+// restore picks the game back up from a save.
+func (gt *GopherTyper) restore(sg *saveGame) error {
+	items := []item{}
+	for _, s := range sg.Items {
+		i, err := loadItem(s)
+		if err != nil {
+			return err
+		}
+		items = append(items, i)
+	}
+	gt.stats = sg.Stats
+	gt.items = items
+	gt.difficulty = sg.Difficulty
+	if sg.Metrics != nil {
+		gt.metrics = sg.Metrics.clone()
+	}
+	for _, p := range gt.packs {
+		if p.name == sg.Pack {
+			gt.pack = p
+		}
+	}
+	return nil
+}
+
+// autosave writes the game to the save file, or removes the save once the game is over.
+// Replays keep track of what the recorded session saved but never touch the file.
+func (gt *GopherTyper) autosave() {
+	if gt.savePath == "" {
+		return
+	}
+	if gt.stats.Lives == 0 {
+		gt.saved = nil
+	} else {
+		gt.saved = gt.save()
+	}
+	if gt.player != nil {
+		return
+	}
+	var err error
+	if gt.saved == nil {
+		err = os.Remove(gt.savePath)
+		if errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+	} else {
+		err = writeSave(gt.savePath, gt.saved)
+	}
+	if err != nil {
+		gt.console.SetText(fmt.Sprintf("Err: %+v", err))
+	}
+}
@@ -0,0 +1,166 @@
+package typeGopher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// scoresVersion is bumped whenever the high score file format changes.
+const scoresVersion = 1
+
+// Score is a player's personal bests.
+type Score struct {
+	Player       string    `json:"player"`
+	HighestLevel int       `json:"highest_level"`
+	TotalEarned  int       `json:"total_earned"`
+	WPM          float64   `json:"wpm"`
+	Accuracy     float64   `json:"accuracy"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// merge keeps the better of each of s's and other's bests.
+func (s *Score) merge(other Score) {
+	if other.HighestLevel > s.HighestLevel {
+		s.HighestLevel = other.HighestLevel
+	}
+	if other.TotalEarned > s.TotalEarned {
+		s.TotalEarned = other.TotalEarned
+	}
+	if other.WPM > s.WPM {
+		s.WPM = other.WPM
+	}
+	if other.Accuracy > s.Accuracy {
+		s.Accuracy = other.Accuracy
+	}
+	if other.UpdatedAt.After(s.UpdatedAt) {
+		s.UpdatedAt = other.UpdatedAt
+	}
+}
+
+// scoresHeader heads the columns of a table of Score rows.
+var scoresHeader = fmt.Sprintf("%-4s %-16s %6s %10s %6s %5s", "#", "Player", "Level", "Earned", "WPM", "Acc")
+
+// row formats s as the given place in a table of scores.
+func (s Score) row(place int) string {
+	return fmt.Sprintf("%-4d %-16.16s %6d %10s %6.1f %4.0f%%", place, s.Player, s.HighestLevel,
+		fmt.Sprintf("$%d", s.TotalEarned), s.WPM, s.Accuracy*100)
+}
+
+// scoresFile is the JSON layout of the high score file.
+type scoresFile struct {
+	Version int     `json:"version"`
+	Scores  []Score `json:"scores"`
+}
+
+// ScoreBoard is a high score table with one entry per player, kept in a
+// file. It is safe to share between games running at the same time.
+type ScoreBoard struct {
+	mu     sync.Mutex
+	path   string
+	scores []Score
+}
+
+// DefaultScoresPath returns where the high score table is kept when no path is given.
+func DefaultScoresPath() (string, error) {
+	return configFilePath("scores.json")
+}
+
+// OpenScoreBoard reads the high score table at path, which need not exist yet.
+func OpenScoreBoard(path string) (*ScoreBoard, error) {
+	b := ScoreBoard{path: path}
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// load reads the high score file, leaving the table empty if there is none.
+func (b *ScoreBoard) load() error {
+	data, err := os.ReadFile(b.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		b.scores = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var f scoresFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("reading %s: %w", b.path, err)
+	}
+	if f.Version != scoresVersion {
+		return fmt.Errorf("reading %s: unsupported scores version %d", b.path, f.Version)
+	}
+	b.scores = f.Scores
+	return nil
+}
+
+// write saves the table, replacing the old file only once the new one is complete.
+func (b *ScoreBoard) write() error {
+	data, err := json.MarshalIndent(scoresFile{Version: scoresVersion, Scores: b.scores}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(b.path, data)
+}
+
+// Record merges s into its player's personal bests and saves the table,
+// returning the player's bests. The file is re-read first so games in other
+// processes aren't overwritten.
+func (b *ScoreBoard) Record(s Score) (Score, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.load(); err != nil {
+		return s, err
+	}
+	for i := range b.scores {
+		if b.scores[i].Player == s.Player {
+			b.scores[i].merge(s)
+			return b.scores[i], b.write()
+		}
+	}
+	b.scores = append(b.scores, s)
+	return s, b.write()
+}
+
+// Scores returns every player's bests, highest level first and then most money earned.
+func (b *ScoreBoard) Scores() []Score {
+	b.mu.Lock()
+	scores := append([]Score(nil), b.scores...)
+	b.mu.Unlock()
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].HighestLevel != scores[j].HighestLevel {
+			return scores[i].HighestLevel > scores[j].HighestLevel
+		}
+		return scores[i].TotalEarned > scores[j].TotalEarned
+	})
+	return scores
+}
+
+// WriteTable writes the high score table to w as plain text, one player per line.
+func (b *ScoreBoard) WriteTable(w io.Writer) error {
+	scores := b.Scores()
+	if len(scores) == 0 {
+		_, err := fmt.Fprintln(w, "No scores yet")
+		return err
+	}
+	if _, err := fmt.Fprintln(w, scoresHeader); err != nil {
+		return err
+	}
+	for i, s := range scores {
+		if _, err := fmt.Fprintln(w, s.row(i+1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package typeGopher
+
+import (
+	tl "gopher_typer/term"
+)
+
+type scoresLevel struct {
+	tl.Level
+	gt *GopherTyper
+	fg tl.Attr
+	bg tl.Attr
+}
+
+// Activate shows the high score table.
+func (l *scoresLevel) Activate() {
+	l.Level = tl.NewBaseLevel(tl.Cell{Bg: l.bg, Fg: l.fg})
+	l.AddEntity(&l.gt.console)
+	l.gt.console.SetText("")
+
+	w, h := l.gt.g.Screen().Size()
+	rect := tl.NewRectangle(10, 2, w-20, h-4, tl.ColorCyan)
+	l.AddEntity(rect)
+
+	msg := "HIGH SCORES"
+	l.AddEntity(tl.NewText(w/2-len(msg)/2, 4, msg, tl.ColorBlue|tl.AttrReverse, tl.ColorDefault))
+
+	x, y := 14, 6
+	msg = scoresHeader
+	l.AddEntity(tl.NewText(x, y, msg, tl.ColorBlack, tl.ColorDefault))
+	y++
+
+	var scores []Score
+	if l.gt.scores != nil {
+		scores = l.gt.scores.Scores()
+	}
+	for i, s := range scores {
+		if y >= h-5 {
+			break
+		}
+		fg := tl.ColorBlack
+		if s.Player == l.gt.playerName {
+			fg = tl.ColorBlue
+		}
+		msg = s.row(i + 1)
+		l.AddEntity(tl.NewText(x, y, msg, fg, tl.ColorDefault))
+		y++
+	}
+	if len(scores) == 0 {
+		msg = "No scores yet"
+		l.AddEntity(tl.NewText(w/2-len(msg)/2, y+1, msg, tl.ColorBlack, tl.ColorDefault))
+	}
+
+	msg = "Press any key to return"
+	l.AddEntity(tl.NewText(w/2-len(msg)/2, h-4, msg, tl.ColorBlack, tl.ColorDefault))
+
+	l.gt.g.Screen().SetLevel(l)
+}
+
+// Tick returns to the intro when a key is pressed.
+func (l *scoresLevel) Tick(e tl.Event) {
+	if !l.gt.handleInput(e) {
+		return
+	}
+	if e.Type == tl.EventKey {
+		l.gt.goToIntro()
+	}
+}
+
+// newScoresLevel creates a new high score level with the given GopherTyper, foreground, and background attributes.
+func newScoresLevel(g *GopherTyper, fg, bg tl.Attr) scoresLevel {
+	return scoresLevel{gt: g, fg: fg, bg: bg}
+}
@@ -12,6 +12,8 @@ type stats struct {
 	Lives           int
 	Garbage         int
 	GarbageFreq     int
+	// SlowedDown is set once any of the run has been played below full speed.
+	SlowedDown bool
 }
 
 // newStats creates and returns a new "stats" object with default values.
@@ -21,8 +23,8 @@ func newStats() stats {
 
 // GarbageCollect if Garbage is > 0 generate a random number between 0 and current garbage
 // If the randomly generated integer is greater than the "GarbageFreq" field of the "stats" struct, the function returns true
-func (s *stats) GarbageCollect() bool {
-	if s.Garbage > 0 && rand.Intn(s.Garbage) > s.GarbageFreq {
+func (s *stats) GarbageCollect(r *rand.Rand) bool {
+	if s.Garbage > 0 && r.Intn(s.Garbage) > s.GarbageFreq {
 		return true
 	}
 	return false
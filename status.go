@@ -0,0 +1,92 @@
+package typeGopher
+
+import (
+	"sync"
+
+	tl "gopher_typer/term"
+)
+
+// Status is a snapshot of a running game.
+type Status struct {
+	Player string
+	// Screen names what the player is looking at, e.g. "intro", "game" or "store".
+	Screen string
+	// Level is the number of the level being played, or about to be.
+	Level int
+	// Won counts the levels completed since the game was started.
+	Won int
+}
+
+// overlay is drawn on top of every level. It shows notices sent with Notify
+// and keeps the game's Status up to date for other goroutines to read.
+type overlay struct {
+	gt     *GopherTyper
+	mu     sync.Mutex
+	notice string
+	status Status
+}
+
+// Draw shows the current notice across the top of the screen and takes a new snapshot of the game.
+func (o *overlay) Draw(s *tl.Screen) {
+	st := Status{
+		Player: o.gt.playerName,
+		Screen: o.gt.screenName(),
+		Level:  o.gt.stats.LevelsCompleted + 1,
+		Won:    o.gt.levelsWon,
+	}
+	o.mu.Lock()
+	o.status = st
+	notice := o.notice
+	o.mu.Unlock()
+
+	if notice != "" {
+		w, _ := s.Size()
+		msg := " " + notice + " "
+		tl.NewText(w/2-len(msg)/2, 0, msg, tl.ColorBlack, tl.ColorYellow).Draw(s)
+	}
+}
+
+// Tick does nothing; the overlay takes no input.
+func (o *overlay) Tick(e tl.Event) {
+}
+
+// screenName names the current level for Status.
+func (gt *GopherTyper) screenName() string {
+	switch gt.level {
+	case &gt.intro:
+		return "intro"
+	case &gt.game:
+		if gt.game.racer != nil {
+			return "race"
+		}
+		if gt.game.paused {
+			return "paused"
+		}
+		return "game"
+	case &gt.store:
+		return "store"
+	case &gt.end:
+		return "end"
+	case &gt.leaderboard:
+		return "scores"
+	case &gt.rooms:
+		return "lobby"
+	}
+	return ""
+}
+
+// Notify shows msg across the top of the player's screen until it is replaced,
+// or cleared with an empty msg. It is safe to call from any goroutine.
+func (gt *GopherTyper) Notify(msg string) {
+	gt.overlay.mu.Lock()
+	defer gt.overlay.mu.Unlock()
+	gt.overlay.notice = msg
+}
+
+// Status returns what the player was doing as of the last frame drawn. It is
+// safe to call from any goroutine.
+func (gt *GopherTyper) Status() Status {
+	gt.overlay.mu.Lock()
+	defer gt.overlay.mu.Unlock()
+	return gt.overlay.status
+}
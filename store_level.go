@@ -2,10 +2,9 @@ package typeGopher
 
 import (
 	"fmt"
-	"os"
 	"time"
 
-	tl "github.com/JoelOtter/termloop"
+	tl "gopher_typer/term"
 )
 
 type storeLevel struct {
@@ -28,7 +27,7 @@ func (l *storeLevel) refresh() {
 	rect := tl.NewRectangle(10, 2, w-20, h-4, tl.ColorGreen)
 	l.AddEntity(rect)
 
-	store, _ := os.ReadFile("data/store.txt")
+	store, _ := readData("store.txt")
 	c := tl.CanvasFromString(string(store))
 	l.AddEntity(tl.NewEntityFromCanvas(w/2-len(c)/2, 4, c))
 
@@ -97,6 +96,9 @@ func (l *storeLevel) purchaseItem(id int) {
 
 // Tick handles the store level input and updates the display accordingly.
 func (l *storeLevel) Tick(e tl.Event) {
+	if !l.gt.handleInput(e) {
+		return
+	}
 	if e.Type == tl.EventKey {
 		if e.Key == tl.KeyArrowDown || e.Ch == 'j' {
 			l.currentItem = (l.currentItem + 1) % len(l.items)
@@ -0,0 +1,359 @@
+package term
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ANSI is a terminal at the other end of a stream, such as an SSH channel.
+// It is drawn with ANSI escape codes and its size is set by the caller,
+// since there is no tty to ask.
+type ANSI struct {
+	rw       io.ReadWriter
+	events   chan Event
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// mu guards closed, which is set once the stream has no more input.
+	mu     sync.RWMutex
+	closed bool
+
+	sizeMu        sync.Mutex
+	width, height int
+	started       bool
+
+	// viewMu guards last and viewers, which Watch adds to from other goroutines.
+	viewMu  sync.Mutex
+	last    Canvas
+	viewers map[*viewer]bool
+}
+
+// viewer is a stream that mirrors what an ANSI terminal draws.
+type viewer struct {
+	frames chan []byte
+	// resync is set when a frame was skipped, so the next one must be drawn in full.
+	resync bool
+	done   chan struct{}
+	exited chan struct{}
+}
+
+// Escape codes that switch to the alternate screen and back.
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l\x1b[2J"
+	leaveScreen = "\x1b[0m\x1b[2J\x1b[?25h\x1b[?1049l"
+)
+
+// NewANSI returns a terminal that reads input from rw and draws to it,
+// starting at the given size.
+func NewANSI(rw io.ReadWriter, width, height int) *ANSI {
+	return &ANSI{
+		rw:     rw,
+		events: make(chan Event),
+		done:   make(chan struct{}),
+		width:  width,
+		height: height,
+	}
+}
+
+// Start switches the terminal to its alternate screen and starts reading input.
+func (t *ANSI) Start() (int, int, error) {
+	if _, err := io.WriteString(t.rw, enterScreen); err != nil {
+		return 0, 0, err
+	}
+	go t.read()
+	t.sizeMu.Lock()
+	defer t.sizeMu.Unlock()
+	t.started = true
+	return t.width, t.height, nil
+}
+
+// Resize tells the game that the terminal is now w by h characters. Once the
+// game has started it blocks until the game has taken the new size.
+func (t *ANSI) Resize(w, h int) {
+	t.sizeMu.Lock()
+	t.width, t.height = w, h
+	started := t.started
+	t.sizeMu.Unlock()
+	if started {
+		t.send(Event{Type: EventResize, Width: w, Height: h})
+	}
+}
+
+// Size returns the terminal's current size.
+func (t *ANSI) Size() (int, int) {
+	t.sizeMu.Lock()
+	defer t.sizeMu.Unlock()
+	return t.width, t.height
+}
+
+// send delivers ev unless the terminal has stopped or run out of input.
+func (t *ANSI) send(ev Event) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		return false
+	}
+	select {
+	case t.events <- ev:
+		return true
+	case <-t.done:
+		return false
+	}
+}
+
+// read turns the bytes coming from the stream into key events, closing
+// Events when the stream ends.
+func (t *ANSI) read() {
+	defer func() {
+		t.mu.Lock()
+		t.closed = true
+		close(t.events)
+		t.mu.Unlock()
+	}()
+
+	buf := make([]byte, 256)
+	var pending []byte
+	for {
+		n, err := t.rw.Read(buf)
+		if n > 0 {
+			var evs []Event
+			evs, pending = parseInput(append(pending, buf[:n]...))
+			for _, ev := range evs {
+				if !t.send(ev) {
+					return
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Events delivers the terminal's input. It is closed when the stream ends.
+func (t *ANSI) Events() <-chan Event {
+	return t.events
+}
+
+// Draw redraws every row of c that differs from the last canvas drawn, on
+// the terminal and on everyone watching it.
+func (t *ANSI) Draw(c Canvas) error {
+	t.viewMu.Lock()
+	frame := render(c, t.last)
+	for v := range t.viewers {
+		f := frame
+		if v.resync {
+			f = render(c, nil)
+		}
+		select {
+		case v.frames <- f:
+			v.resync = false
+		default:
+			v.resync = true
+		}
+	}
+	t.last = c
+	t.viewMu.Unlock()
+
+	_, err := t.rw.Write(frame)
+	return err
+}
+
+// Watch mirrors the terminal onto w, starting with the whole of the current
+// screen, until stop is called or writing to w fails. Frames w can't keep
+// up with are skipped rather than holding up the game.
+func (t *ANSI) Watch(w io.Writer) (stop func()) {
+	v := &viewer{frames: make(chan []byte, 4), done: make(chan struct{}), exited: make(chan struct{})}
+	t.viewMu.Lock()
+	if t.viewers == nil {
+		t.viewers = map[*viewer]bool{}
+	}
+	t.viewers[v] = true
+	v.frames <- append([]byte(enterScreen), render(t.last, nil)...)
+	t.viewMu.Unlock()
+
+	remove := func() {
+		t.viewMu.Lock()
+		delete(t.viewers, v)
+		t.viewMu.Unlock()
+	}
+	go func() {
+		defer close(v.exited)
+		for {
+			select {
+			case f := <-v.frames:
+				if _, err := w.Write(f); err != nil {
+					remove()
+					return
+				}
+			case <-v.done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			remove()
+			close(v.done)
+			<-v.exited
+			io.WriteString(w, leaveScreen)
+		})
+	}
+}
+
+// render returns the escape codes that turn last into c, redrawing only the
+// rows that changed, or everything if the two differ in size.
+func render(c, last Canvas) []byte {
+	var b bytes.Buffer
+	full := len(c) != len(last) || len(c) > 0 && len(c[0]) != len(last[0])
+	if full {
+		b.WriteString("\x1b[0m\x1b[2J")
+	}
+	height := 0
+	if len(c) > 0 {
+		height = len(c[0])
+	}
+	for y := 0; y < height; y++ {
+		if !full && !rowChanged(c, last, y) {
+			continue
+		}
+		fmt.Fprintf(&b, "\x1b[%d;1H", y+1)
+		style := ""
+		for x := 0; x < len(c); x++ {
+			cell := c[x][y]
+			if s := sgr(cell.Fg, cell.Bg); s != style {
+				b.WriteString(s)
+				style = s
+			}
+			ch := cell.Ch
+			if ch < ' ' {
+				ch = ' '
+			}
+			b.WriteRune(ch)
+			if runewidth.RuneWidth(ch) == 2 {
+				x++
+			}
+		}
+	}
+	b.WriteString("\x1b[0m")
+	return b.Bytes()
+}
+
+// rowChanged reports whether row y differs between two canvases of the same size.
+func rowChanged(c, last Canvas, y int) bool {
+	for x := range c {
+		if c[x][y] != last[x][y] {
+			return true
+		}
+	}
+	return false
+}
+
+// sgr returns the escape code that selects the given colors and attributes.
+func sgr(fg, bg Attr) string {
+	s := "\x1b[0"
+	if fg&AttrBold != 0 {
+		s += ";1"
+	}
+	if fg&AttrUnderline != 0 {
+		s += ";4"
+	}
+	if (fg|bg)&AttrReverse != 0 {
+		s += ";7"
+	}
+	if c := fg & colorMask; c != ColorDefault {
+		s += ";38;5;" + strconv.Itoa(int(c-1))
+	}
+	if c := bg & colorMask; c != ColorDefault {
+		s += ";48;5;" + strconv.Itoa(int(c-1))
+	}
+	return s + "m"
+}
+
+// Stop leaves the alternate screen and stops delivering input.
+func (t *ANSI) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.done)
+		io.WriteString(t.rw, leaveScreen)
+	})
+}
+
+// escapeKeys maps the final byte of an escape sequence to its key.
+var escapeKeys = map[byte]Key{
+	'A': KeyArrowUp,
+	'B': KeyArrowDown,
+	'C': KeyArrowRight,
+	'D': KeyArrowLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'R': KeyF3,
+	'S': KeyF4,
+}
+
+// tildeKeys maps the number in an escape sequence ending in '~' to its key.
+var tildeKeys = map[string]Key{
+	"1": KeyHome,
+	"2": KeyInsert,
+	"3": KeyDelete,
+	"4": KeyEnd,
+	"5": KeyPgup,
+	"6": KeyPgdn,
+	"7": KeyHome,
+	"8": KeyEnd,
+}
+
+// maxPending is the longest incomplete sequence kept waiting for more input.
+const maxPending = 16
+
+// parseInput turns raw terminal input into key events, returning any
+// incomplete sequence at the end so it can be finished by the next read.
+// A lone escape is the Esc key, since there is no way to wait for the rest
+// of a sequence that may never come.
+func parseInput(b []byte) ([]Event, []byte) {
+	var evs []Event
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b && len(b) > 1 && (b[1] == '[' || b[1] == 'O'):
+			end := bytes.IndexFunc(b[2:], func(r rune) bool { return r >= 0x40 && r <= 0x7e })
+			if end < 0 {
+				if len(b) > maxPending {
+					return evs, nil
+				}
+				return evs, b
+			}
+			params, final := string(b[2:2+end]), b[2+end]
+			if final == '~' {
+				if k, ok := tildeKeys[params]; ok {
+					evs = append(evs, Event{Type: EventKey, Key: k})
+				}
+			} else if k, ok := escapeKeys[final]; ok {
+				evs = append(evs, Event{Type: EventKey, Key: k})
+			}
+			b = b[3+end:]
+		case c < ' ' || c == 0x7f:
+			evs = append(evs, Event{Type: EventKey, Key: Key(c)})
+			b = b[1:]
+		case c == ' ':
+			evs = append(evs, Event{Type: EventKey, Key: KeySpace})
+			b = b[1:]
+		default:
+			if !utf8.FullRune(b) {
+				return evs, b
+			}
+			r, n := utf8.DecodeRune(b)
+			evs = append(evs, Event{Type: EventKey, Ch: r})
+			b = b[n:]
+		}
+	}
+	return evs, nil
+}
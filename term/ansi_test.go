@@ -0,0 +1,57 @@
+package term
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// key and char are the events parseInput makes for a special key and a character.
+func key(k Key) Event   { return Event{Type: EventKey, Key: k} }
+func char(r rune) Event { return Event{Type: EventKey, Ch: r} }
+
+func TestParseInput(t *testing.T) {
+	tests := []struct {
+		name string
+		// reads are the chunks the input arrives in, one per read.
+		reads []string
+		want  []Event
+	}{
+		{"letters", []string{"go"}, []Event{char('g'), char('o')}},
+		{"wide characters", []string{"é世"}, []Event{char('é'), char('世')}},
+		{"control keys", []string{"\t\r\x7f\x03 "}, []Event{key(KeyTab), key(KeyEnter), key(KeyBackspace2), key(KeyCtrlC), key(KeySpace)}},
+		{"arrow", []string{"\x1b[A"}, []Event{key(KeyArrowUp)}},
+		{"application mode key", []string{"\x1bOP"}, []Event{key(KeyF1)}},
+		{"tilde key", []string{"\x1b[3~"}, []Event{key(KeyDelete)}},
+		{"keys around a sequence", []string{"a\x1b[Db"}, []Event{char('a'), key(KeyArrowLeft), char('b')}},
+		{"unknown sequence is dropped", []string{"\x1b[Zg"}, []Event{char('g')}},
+		{"unknown tilde key is dropped", []string{"\x1b[99~g"}, []Event{char('g')}},
+		{"lone escape", []string{"\x1b"}, []Event{key(KeyEsc)}},
+		{"escape before a letter", []string{"\x1bg"}, []Event{key(KeyEsc), char('g')}},
+		{"sequence split after the bracket", []string{"\x1b[", "A"}, []Event{key(KeyArrowUp)}},
+		{"sequence split in its parameters", []string{"\x1b[1", ";5C"}, []Event{key(KeyArrowRight)}},
+		{"sequence split over three reads", []string{"\x1b[", "3", "~"}, []Event{key(KeyDelete)}},
+		{"sequence split after a key", []string{"g\x1b[", "B"}, []Event{char('g'), key(KeyArrowDown)}},
+		// A read ending in a lone escape can't wait for the rest, so it is the Esc key.
+		{"sequence split after the escape", []string{"\x1b", "[A"}, []Event{key(KeyEsc), char('['), char('A')}},
+		{"character split across reads", []string{"\xc3", "\xa9"}, []Event{char('é')}},
+		{"overlong sequence is dropped", []string{"\x1b[" + strings.Repeat("1", maxPending), "g"}, []Event{char('g')}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Event
+			var pending []byte
+			for _, r := range tt.reads {
+				var evs []Event
+				evs, pending = parseInput(append(pending, r...))
+				got = append(got, evs...)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInput() = %v, want %v", got, tt.want)
+			}
+			if len(pending) > 0 {
+				t.Errorf("left %q pending, want nothing", pending)
+			}
+		})
+	}
+}
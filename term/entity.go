@@ -0,0 +1,147 @@
+package term
+
+import "github.com/mattn/go-runewidth"
+
+// Text represents a string that can be drawn to the screen.
+type Text struct {
+	x      int
+	y      int
+	fg     Attr
+	bg     Attr
+	text   []rune
+	canvas []Cell
+}
+
+// NewText creates a new Text, at position (x, y). It sets the Text's
+// foreground and background colors to fg and bg respectively, and sets the
+// Text's text to be text.
+// Returns a pointer to the new Text.
+func NewText(x, y int, text string, fg, bg Attr) *Text {
+	t := Text{x: x, y: y, fg: fg, bg: bg}
+	t.SetText(text)
+	return &t
+}
+
+// Tick needs to be implemented to satisfy the Drawable interface.
+func (t *Text) Tick(ev Event) {}
+
+// Draw draws the Text to the Screen s.
+func (t *Text) Draw(s *Screen) {
+	for i := range t.canvas {
+		s.RenderCell(t.x+i, t.y, &t.canvas[i])
+	}
+}
+
+// Position returns the (x, y) coordinates of the Text.
+func (t *Text) Position() (int, int) {
+	return t.x, t.y
+}
+
+// Size returns the width in cells and height of the Text.
+func (t *Text) Size() (int, int) {
+	return len(t.canvas), 1
+}
+
+// SetPosition sets the coordinates of the Text to be (x, y).
+func (t *Text) SetPosition(x, y int) {
+	t.x = x
+	t.y = y
+}
+
+// Text returns the text of the Text.
+func (t *Text) Text() string {
+	return string(t.text)
+}
+
+// SetText sets the text of the Text to be text. Wide characters take two
+// cells, the second of them left blank.
+func (t *Text) SetText(text string) {
+	t.text = []rune(text)
+	t.canvas = make([]Cell, 0, len(t.text))
+	for _, ch := range t.text {
+		t.canvas = append(t.canvas, Cell{Ch: ch, Fg: t.fg, Bg: t.bg})
+		if runewidth.RuneWidth(ch) == 2 {
+			t.canvas = append(t.canvas, Cell{Ch: ' ', Fg: t.fg, Bg: t.bg})
+		}
+	}
+}
+
+// Color returns the (foreground, background) colors of the Text.
+func (t *Text) Color() (Attr, Attr) {
+	return t.fg, t.bg
+}
+
+// SetColor sets the (foreground, background) colors of the Text
+// to fg, bg respectively.
+func (t *Text) SetColor(fg, bg Attr) {
+	t.fg = fg
+	t.bg = bg
+	for i := range t.canvas {
+		t.canvas[i].Fg = fg
+		t.canvas[i].Bg = bg
+	}
+}
+
+// Rectangle is a block of a single color.
+type Rectangle struct {
+	x      int
+	y      int
+	width  int
+	height int
+	color  Attr
+}
+
+// NewRectangle creates a new Rectangle at position (x, y), with size
+// (width, height) and color color.
+// Returns a pointer to the new Rectangle.
+func NewRectangle(x, y, w, h int, color Attr) *Rectangle {
+	return &Rectangle{x: x, y: y, width: w, height: h, color: color}
+}
+
+// Draw draws the Rectangle r onto Screen s.
+func (r *Rectangle) Draw(s *Screen) {
+	for i := 0; i < r.width; i++ {
+		for j := 0; j < r.height; j++ {
+			s.RenderCell(r.x+i, r.y+j, &Cell{Bg: r.color, Ch: ' '})
+		}
+	}
+}
+
+// Tick needs to be implemented to satisfy the Drawable interface.
+func (r *Rectangle) Tick(ev Event) {}
+
+// Entity is a Drawable made from a Canvas.
+type Entity struct {
+	canvas Canvas
+	x      int
+	y      int
+}
+
+// NewEntityFromCanvas returns a pointer to a new Entity, with
+// position (x, y) and Canvas c.
+func NewEntityFromCanvas(x, y int, c Canvas) *Entity {
+	return &Entity{x: x, y: y, canvas: c}
+}
+
+// Draw draws the entity to its current position on the screen.
+func (e *Entity) Draw(s *Screen) {
+	for i := range e.canvas {
+		for j := range e.canvas[i] {
+			s.RenderCell(e.x+i, e.y+j, &e.canvas[i][j])
+		}
+	}
+}
+
+// Tick needs to be implemented to satisfy the Drawable interface.
+func (e *Entity) Tick(ev Event) {}
+
+// Position returns the (x, y) coordinates of the Entity.
+func (e *Entity) Position() (int, int) {
+	return e.x, e.y
+}
+
+// SetPosition sets the x and y coordinates of the Entity.
+func (e *Entity) SetPosition(x, y int) {
+	e.x = x
+	e.y = y
+}
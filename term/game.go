@@ -0,0 +1,85 @@
+package term
+
+import (
+	"sync"
+	"time"
+)
+
+// Game runs a Screen on a Terminal, passing it input and redrawing it every frame.
+type Game struct {
+	screen   *Screen
+	terminal Terminal
+	endKey   Key
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewGame creates a new Game that runs on t, along with a Screen.
+// Returns a pointer to the new Game.
+func NewGame(t Terminal) *Game {
+	return &Game{
+		screen:   NewScreen(),
+		terminal: t,
+		endKey:   KeyCtrlC,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Screen returns the current Screen of a Game.
+func (g *Game) Screen() *Screen {
+	return g.screen
+}
+
+// SetEndKey sets the Key used to end the game. Default is KeyCtrlC.
+func (g *Game) SetEndKey(key Key) {
+	g.endKey = key
+}
+
+// Start runs the Game until the end key is pressed, Stop is called or the
+// terminal goes away.
+func (g *Game) Start() error {
+	w, h, err := g.terminal.Start()
+	if err != nil {
+		return err
+	}
+	defer g.terminal.Stop()
+	g.screen.resize(w, h)
+
+	events := g.terminal.Events()
+	clock := time.Now()
+	for {
+		update := time.Now()
+		g.screen.delta = update.Sub(clock).Seconds()
+		clock = update
+
+		select {
+		case <-g.stop:
+			return nil
+		case ev, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if ev.Type == EventKey && ev.Ch == 0 && ev.Key == g.endKey {
+				return nil
+			}
+			if ev.Type == EventResize {
+				g.screen.resize(ev.Width, ev.Height)
+			}
+			g.screen.Tick(ev)
+		default:
+			g.screen.Tick(Event{Type: EventNone})
+		}
+
+		if err := g.screen.draw(g.terminal); err != nil {
+			return err
+		}
+		if g.screen.fps > 0 {
+			time.Sleep(time.Until(update.Add(time.Duration(float64(time.Second) / g.screen.fps))))
+		}
+	}
+}
+
+// Stop makes Start return after the current frame. It is safe to call from any goroutine.
+func (g *Game) Stop() {
+	g.stopOnce.Do(func() { close(g.stop) })
+}
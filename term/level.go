@@ -0,0 +1,62 @@
+package term
+
+// Level interface represents a Drawable with a separate background
+// that is drawn first. It can also contain Drawables of its own.
+type Level interface {
+	DrawBackground(*Screen)
+	AddEntity(Drawable)
+	RemoveEntity(Drawable)
+	Draw(*Screen)
+	Tick(Event)
+}
+
+// BaseLevel type represents a Level with a background defined as a Cell,
+// which is tiled. The background is drawn first, then all Entities.
+type BaseLevel struct {
+	Entities []Drawable
+	bg       Cell
+}
+
+// NewBaseLevel creates a new BaseLevel with background bg.
+// Returns a pointer to the new BaseLevel.
+func NewBaseLevel(bg Cell) *BaseLevel {
+	return &BaseLevel{Entities: make([]Drawable, 0), bg: bg}
+}
+
+// Tick passes ev to each of the level's entities.
+func (l *BaseLevel) Tick(ev Event) {
+	for _, e := range l.Entities {
+		e.Tick(ev)
+	}
+}
+
+// DrawBackground draws the background Cell bg to each Cell of the Screen s.
+func (l *BaseLevel) DrawBackground(s *Screen) {
+	for i, col := range s.canvas {
+		for j := range col {
+			s.canvas[i][j] = l.bg
+		}
+	}
+}
+
+// Draw draws the level's entities to the Screen s.
+func (l *BaseLevel) Draw(s *Screen) {
+	for _, e := range l.Entities {
+		e.Draw(s)
+	}
+}
+
+// AddEntity adds Drawable d to the level's entities.
+func (l *BaseLevel) AddEntity(d Drawable) {
+	l.Entities = append(l.Entities, d)
+}
+
+// RemoveEntity removes Drawable d from the level's entities.
+func (l *BaseLevel) RemoveEntity(d Drawable) {
+	for i, elem := range l.Entities {
+		if elem == d {
+			l.Entities = append(l.Entities[:i], l.Entities[i+1:]...)
+			return
+		}
+	}
+}
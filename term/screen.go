@@ -0,0 +1,120 @@
+package term
+
+// A Screen represents the current state of the display.
+// To draw on the screen, create Drawables and set their positions.
+// Then, add them to the Screen's Level, or to the Screen directly (e.g. a HUD).
+type Screen struct {
+	oldCanvas Canvas
+	canvas    Canvas
+	level     Level
+	Entities  []Drawable
+	width     int
+	height    int
+	delta     float64
+	fps       float64
+}
+
+// NewScreen creates a new Screen, with no entities or level.
+// Returns a pointer to the new Screen.
+func NewScreen() *Screen {
+	return &Screen{Entities: make([]Drawable, 0), canvas: NewCanvas(10, 10)}
+}
+
+// Tick is used to process events such as input. It is called
+// on every frame by the Game.
+func (s *Screen) Tick(ev Event) {
+	if s.level != nil {
+		s.level.Tick(ev)
+	}
+	if ev.Type != EventNone {
+		for _, e := range s.Entities {
+			e.Tick(ev)
+		}
+	}
+}
+
+// draw renders the current state of the screen, and sends it to t if
+// anything has changed since the last frame.
+func (s *Screen) draw(t Terminal) error {
+	s.canvas = NewCanvas(s.width, s.height)
+	if s.level != nil {
+		s.level.DrawBackground(s)
+		s.level.Draw(s)
+	}
+	for _, e := range s.Entities {
+		e.Draw(s)
+	}
+	if s.canvas.equals(s.oldCanvas) {
+		return nil
+	}
+	s.oldCanvas = s.canvas
+	return t.Draw(s.canvas)
+}
+
+// resize changes the size of the screen to w by h characters.
+func (s *Screen) resize(w, h int) {
+	s.width = w
+	s.height = h
+	s.canvas = NewCanvas(w, h)
+}
+
+// Size returns the width and height of the Screen
+// in characters.
+func (s *Screen) Size() (int, int) {
+	return s.width, s.height
+}
+
+// SetLevel sets the Screen's current level to be l.
+func (s *Screen) SetLevel(l Level) {
+	s.level = l
+}
+
+// Level returns the Screen's current level.
+func (s *Screen) Level() Level {
+	return s.level
+}
+
+// AddEntity adds a Drawable to the current Screen, to be rendered.
+func (s *Screen) AddEntity(d Drawable) {
+	s.Entities = append(s.Entities, d)
+}
+
+// RemoveEntity removes Drawable d from the screen's entities.
+func (s *Screen) RemoveEntity(d Drawable) {
+	for i, elem := range s.Entities {
+		if elem == d {
+			s.Entities = append(s.Entities[:i], s.Entities[i+1:]...)
+			return
+		}
+	}
+}
+
+// TimeDelta returns the number of seconds since the previous
+// frame was rendered. Can be used for timings and animation.
+func (s *Screen) TimeDelta() float64 {
+	return s.delta
+}
+
+// SetFps sets the screen framerate. By default the screen is drawn
+// as fast as possible, which may use a lot of system resources.
+func (s *Screen) SetFps(f float64) {
+	s.fps = f
+}
+
+// RenderCell updates the Cell at a given position on the Screen
+// with the attributes in Cell c.
+func (s *Screen) RenderCell(x, y int, c *Cell) {
+	if x < 0 || x >= len(s.canvas) || y < 0 || y >= len(s.canvas[x]) {
+		return
+	}
+	old := &s.canvas[x][y]
+	if c.Ch != 0 {
+		old.Ch = c.Ch
+	}
+	if c.Bg != 0 {
+		old.Bg = c.Bg
+	}
+	if c.Fg != 0 {
+		old.Fg = c.Fg
+	}
+}
@@ -0,0 +1,192 @@
+// Package term is a small termloop-style game loop that can draw to any
+// terminal, not just the one the process is attached to. termloop drives
+// termbox, which owns the process's tty, so it can only run one game at a
+// time; term lets a server run a game per connection.
+//
+// Much of the package is adapted from termloop
+// (https://github.com/JoelOtter/termloop) and is covered by its MIT licence,
+// reproduced in the LICENSE file next to this one.
+package term
+
+import (
+	"strings"
+)
+
+type (
+	Attr      uint16
+	Key       uint16
+	Modifier  uint8
+	EventType uint8
+)
+
+// Types of event. For example, a keyboard press will be EventKey.
+const (
+	EventKey EventType = iota
+	EventResize
+	EventMouse
+	EventError
+	EventInterrupt
+	EventRaw
+	EventNone
+)
+
+// Cell colors. You can combine these with multiple attributes using
+// a bitwise OR ('|'). Colors can't combine with other colors.
+const (
+	ColorDefault Attr = iota
+	ColorBlack
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+)
+
+// Cell attributes. These can be combined with OR.
+const (
+	AttrBold Attr = 1 << (iota + 9)
+	AttrUnderline
+	AttrReverse
+)
+
+// colorMask selects the color from an Attr, leaving out the attributes.
+const colorMask = AttrBold - 1
+
+// ModAlt is set on key events typed with the Alt key held down.
+const ModAlt Modifier = 0x01
+
+// Key constants. See Event.Key.
+const (
+	KeyF1 Key = 0xFFFF - iota
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyInsert
+	KeyDelete
+	KeyHome
+	KeyEnd
+	KeyPgup
+	KeyPgdn
+	KeyArrowUp
+	KeyArrowDown
+	KeyArrowLeft
+	KeyArrowRight
+)
+
+const (
+	KeyCtrlA      Key = 0x01
+	KeyCtrlC      Key = 0x03
+	KeyCtrlD      Key = 0x04
+	KeyBackspace  Key = 0x08
+	KeyTab        Key = 0x09
+	KeyEnter      Key = 0x0D
+	KeyEsc        Key = 0x1B
+	KeySpace      Key = 0x20
+	KeyBackspace2 Key = 0x7F
+)
+
+// Cell is a character to be drawn on the screen.
+type Cell struct {
+	Fg Attr // Foreground colour
+	Bg Attr // Background color
+	Ch rune // The character to draw
+}
+
+// Event is a key press, a change of terminal size or an error.
+type Event struct {
+	Type   EventType // The type of event
+	Key    Key       // The key pressed, if any
+	Ch     rune      // The character of the key, if any
+	Mod    Modifier  // A keyboard modifier, if any
+	Err    error     // Error, if any
+	Width  int       // The new width, for resize events
+	Height int       // The new height, for resize events
+}
+
+// Drawable represents something that can be drawn, and placed in a Level.
+type Drawable interface {
+	Tick(Event)   // Method for processing events, e.g. input
+	Draw(*Screen) // Method for drawing to the screen
+}
+
+// A Canvas is a 2D array of Cells, used for drawing.
+// The structure of a Canvas is an array of columns.
+// This is so it can be addressed canvas[x][y].
+type Canvas [][]Cell
+
+// NewCanvas returns a new Canvas, with
+// width and height defined by arguments.
+func NewCanvas(width, height int) Canvas {
+	canvas := make(Canvas, width)
+	for i := range canvas {
+		canvas[i] = make([]Cell, height)
+	}
+	return canvas
+}
+
+// equals reports whether two canvases hold the same cells.
+func (canvas Canvas) equals(other Canvas) bool {
+	if len(canvas) != len(other) {
+		return false
+	}
+	for i := range canvas {
+		if len(canvas[i]) != len(other[i]) {
+			return false
+		}
+		for j := range canvas[i] {
+			if canvas[i][j] != other[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CanvasFromString returns a new Canvas, built from
+// the characters in the string str. Newline characters in
+// the string are interpreted as a new Canvas row.
+func CanvasFromString(str string) Canvas {
+	lines := strings.Split(str, "\n")
+	runes := make([][]rune, len(lines))
+	width := 0
+	for i := range lines {
+		runes[i] = []rune(lines[i])
+		if len(runes[i]) > width {
+			width = len(runes[i])
+		}
+	}
+	height := len(runes)
+	canvas := make(Canvas, width)
+	for i := 0; i < width; i++ {
+		canvas[i] = make([]Cell, height)
+		for j := 0; j < height; j++ {
+			if i < len(runes[j]) {
+				canvas[i][j] = Cell{Ch: runes[j][i]}
+			}
+		}
+	}
+	return canvas
+}
+
+// Terminal is a display and keyboard that a Game runs on.
+type Terminal interface {
+	// Start prepares the terminal for drawing and returns its size.
+	Start() (width, height int, err error)
+	// Events delivers key presses and size changes. It is closed when the
+	// terminal goes away.
+	Events() <-chan Event
+	// Draw shows c on the terminal.
+	Draw(c Canvas) error
+	// Stop restores the terminal to how it was before Start.
+	Stop()
+}
@@ -0,0 +1,91 @@
+package term
+
+import (
+	"github.com/nsf/termbox-go"
+)
+
+// termboxTerminal is the terminal the process is attached to.
+type termboxTerminal struct {
+	events chan Event
+	done   chan struct{}
+}
+
+// NewTermbox returns the terminal the process is attached to. Only one
+// may be started at a time.
+func NewTermbox() Terminal {
+	return &termboxTerminal{}
+}
+
+// Start takes over the terminal and starts reading input from it.
+func (t *termboxTerminal) Start() (int, int, error) {
+	if err := termbox.Init(); err != nil {
+		return 0, 0, err
+	}
+	termbox.SetOutputMode(termbox.Output256)
+	termbox.SetInputMode(termbox.InputEsc)
+	t.events = make(chan Event)
+	t.done = make(chan struct{})
+	go t.poll()
+	w, h := termbox.Size()
+	return w, h, nil
+}
+
+// poll passes termbox events on until it is interrupted by Stop.
+func (t *termboxTerminal) poll() {
+	for {
+		ev := termbox.PollEvent()
+		if ev.Type == termbox.EventInterrupt {
+			return
+		}
+		select {
+		case t.events <- Event{
+			Type:   EventType(ev.Type),
+			Key:    Key(ev.Key),
+			Ch:     ev.Ch,
+			Mod:    Modifier(ev.Mod),
+			Err:    ev.Err,
+			Width:  ev.Width,
+			Height: ev.Height,
+		}:
+		case <-t.done:
+			// Keep polling so Stop's interrupt is received.
+		}
+	}
+}
+
+// Events delivers the terminal's input.
+func (t *termboxTerminal) Events() <-chan Event {
+	return t.events
+}
+
+// Draw shows c on the terminal.
+func (t *termboxTerminal) Draw(c Canvas) error {
+	for x, col := range c {
+		for y, cell := range col {
+			termbox.SetCell(x, y, cell.Ch, termboxAttr(cell.Fg), termboxAttr(cell.Bg))
+		}
+	}
+	return termbox.Flush()
+}
+
+// Stop gives the terminal back.
+func (t *termboxTerminal) Stop() {
+	close(t.done)
+	termbox.Interrupt()
+	termbox.Close()
+}
+
+// termboxAttr converts a color and its attributes to termbox's.
+func termboxAttr(a Attr) termbox.Attribute {
+	ta := termbox.Attribute(a & colorMask)
+	if a&AttrBold != 0 {
+		ta |= termbox.AttrBold
+	}
+	if a&AttrUnderline != 0 {
+		ta |= termbox.AttrUnderline
+	}
+	if a&AttrReverse != 0 {
+		ta |= termbox.AttrReverse
+	}
+	return ta
+}
@@ -1,81 +1,145 @@
 package typeGopher
 
 import (
+	"math"
 	"time"
+	"unicode/utf8"
 
-	tl "github.com/JoelOtter/termloop"
+	"github.com/mattn/go-runewidth"
 )
 
 type word struct {
-	str                   string
-	createdAt             time.Time
-	v                     float64
-	startedBy             int
-	completedChars        int
-	deleteAt              time.Time
-	x, y, baseY           int
-	fgComplete, fgTodo    tl.Attr
-	bgPlayer, bgGoroutine tl.Attr
+	str            string
+	chars          []rune
+	lines          []string
+	createdAt      time.Time
+	v              float64
+	startedBy      int
+	completedChars int
+	// wrong holds the characters typed since the first mistake, until they are erased.
+	wrong       []rune
+	finished    bool
+	targetedAt  time.Time
+	firstKeyAt  time.Time
+	mistakes    int
+	x, y, baseY int
 }
 
 const pc = -1
 
-// newWord creates a new word instance with the given coordinates, value, and attributes.
-func newWord(x, y int, val string, fgComplete, fgTodo, bgPlayer, bgGoroutine tl.Attr) *word {
-	return &word{str: val, createdAt: time.Now(), v: 2, x: x, y: y, baseY: y, fgComplete: fgComplete, fgTodo: fgTodo, bgPlayer: bgPlayer, bgGoroutine: bgGoroutine}
+// newWord creates a new word instance with the given coordinates and value, falling at v rows per second from the given time.
+// Values longer than maxWidth are wrapped over several lines.
+func newWord(x, y int, val string, v float64, maxWidth int, now time.Time) *word {
+	return &word{str: val, chars: []rune(val), lines: wrapText(val, maxWidth), createdAt: now, v: v, x: x, y: y, baseY: y}
 }
 
-// Draw renders the word on the screen with the appropriate colors.
-func (w *word) Draw(s *tl.Screen) {
-	for i, ch := range w.str {
-		if w.startedBy == 0 {
-			s.RenderCell(w.x+i, w.y, &tl.Cell{Fg: w.fgTodo, Bg: tl.ColorDefault, Ch: ch})
-		} else {
-			var bg tl.Attr
-			if w.startedBy == pc {
-				bg = w.bgPlayer
-			} else {
-				bg = w.bgGoroutine
+// wrapText splits s into lines no wider than width cells, breaking after a
+// space where possible. Joining the lines back together gives s.
+func wrapText(s string, width int) []string {
+	var lines []string
+	for width > 0 && runewidth.StringWidth(s) > width {
+		cut, lastSpace, w := 0, 0, 0
+		for i, r := range s {
+			if r == ' ' {
+				// A space ending a line may hang off the edge.
+				lastSpace = i + 1
 			}
-			if i < w.completedChars {
-				s.RenderCell(w.x+i, w.y, &tl.Cell{Fg: w.fgComplete, Bg: bg, Ch: ch})
-			} else {
-				s.RenderCell(w.x+i, w.y, &tl.Cell{Fg: w.fgTodo, Bg: bg, Ch: ch})
+			w += runewidth.RuneWidth(r)
+			if w > width {
+				break
 			}
+			cut = i + utf8.RuneLen(r)
 		}
+		if lastSpace > 0 {
+			cut = lastSpace
+		}
+		if cut == 0 {
+			// A single character wider than the field gets a line to itself.
+			_, cut = utf8.DecodeRuneInString(s)
+		}
+		lines = append(lines, s[:cut])
+		s = s[cut:]
 	}
+	return append(lines, s)
 }
 
-// Tick is a placeholder method for the word entity.
-func (w *word) Tick(e tl.Event) {
+// Size returns the width in cells and height the word takes up on screen.
+func (w *word) Size() (int, int) {
+	width := 0
+	for _, line := range w.lines {
+		if lw := runewidth.StringWidth(line); lw > width {
+			width = lw
+		}
+	}
+	return width, len(w.lines)
 }
 
-// Complete checks if the word has been fully completed or not.
+// Complete checks if every character of the word has been typed.
 func (w *word) Complete() bool {
-	return w.completedChars == len(w.str)
+	return w.completedChars == len(w.chars)
 }
 
-// Update updates the word's position and background attributes based on completion.
-func (w *word) Update() {
-	w.y = w.baseY + int((time.Now().Sub(w.createdAt)).Seconds()*w.v)
+// remaining returns the part of the word still to be typed.
+func (w *word) remaining() string {
+	return string(w.chars[w.completedChars:])
+}
+
+// Update updates the word's position based on how long it has been falling.
+func (w *word) Update(now time.Time) {
+	w.y = w.baseY + int((now.Sub(w.createdAt)).Seconds()*w.v)
+}
 
-	if w.Complete() {
-		w.bgPlayer = tl.AttrUnderline
-		w.bgGoroutine = tl.AttrUnderline
+// timeToFloor returns how long after now the word's bottom line will fall below the floor row.
+func (w *word) timeToFloor(now time.Time, floor int) time.Duration {
+	if w.v <= 0 {
+		return time.Duration(math.MaxInt64)
 	}
+	_, h := w.Size()
+	rows := floor - (w.baseY + h - 1) + 1
+	hitAt := w.createdAt.Add(time.Duration(float64(rows) / w.v * float64(time.Second)))
+	return hitAt.Sub(now)
 }
 
-// KeyDown handles character input and updates the word's state accordingly.
-func (w *word) KeyDown(ch rune) {
-	found := false
-	for i, r := range w.str {
-		if i == w.completedChars && r == ch {
-			w.completedChars++
-			found = true
-			break
+// hasPrefix reports whether the word starts with prefix.
+func (w *word) hasPrefix(prefix []rune) bool {
+	if len(prefix) > len(w.chars) {
+		return false
+	}
+	for i, ch := range prefix {
+		if w.chars[i] != ch {
+			return false
 		}
 	}
-	if !found {
-		w.createdAt = w.createdAt.Add(-1 * time.Second)
+	return true
+}
+
+// untarget puts the word back as if nobody had started it.
+func (w *word) untarget() {
+	w.startedBy = 0
+	w.completedChars = 0
+	w.wrong = nil
+	w.mistakes = 0
+	w.targetedAt, w.firstKeyAt = time.Time{}, time.Time{}
+}
+
+// KeyDown types ch into the word and reports whether it was correct. A
+// wrong character, and any typed after it, is kept until it is erased.
+func (w *word) KeyDown(ch rune) bool {
+	if len(w.wrong) == 0 && w.completedChars < len(w.chars) && w.chars[w.completedChars] == ch {
+		w.completedChars++
+		return true
+	}
+	if w.completedChars+len(w.wrong) < len(w.chars) {
+		w.wrong = append(w.wrong, ch)
+	}
+	return false
+}
+
+// Erase removes the last wrong character typed and reports whether there was one.
+func (w *word) Erase() bool {
+	if len(w.wrong) == 0 {
+		return false
 	}
+	w.wrong = w.wrong[:len(w.wrong)-1]
+	return true
 }
@@ -2,17 +2,215 @@ package typeGopher
 
 import (
 	"bufio"
+	"bytes"
+	"fmt"
 	"io"
+	"math/rand"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
-// newWordLoader reads words from an io.Reader and returns them as a slice of strings.
-func newWordLoader(r io.Reader) []string {
-	var words []string
+// wordPack is a named list of words to play with. Words with a higher weight come up more often.
+type wordPack struct {
+	name        string
+	description string
+	phrases     bool
+	words       []string
+	weights     []int
+	totalWeight int
+}
+
+// pick returns a random word from the pack, taking the weights into account.
+func (p *wordPack) pick(r *rand.Rand) string {
+	n := r.Intn(p.totalWeight)
+	for i, w := range p.weights {
+		if n < w {
+			return p.words[i]
+		}
+		n -= w
+	}
+	return p.words[len(p.words)-1]
+}
+
+// pickLength returns a random word from the pack between minLen and maxLen
+// characters long, where a maxLen of 0 means any length. If no word fits it
+// falls back to any word.
+func (p *wordPack) pickLength(r *rand.Rand, minLen, maxLen int) string {
+	if minLen <= 0 && maxLen <= 0 {
+		return p.pick(r)
+	}
+	fits := func(word string) bool {
+		n := utf8.RuneCountInString(word)
+		return n >= minLen && (maxLen <= 0 || n <= maxLen)
+	}
+	total := 0
+	for i, word := range p.words {
+		if fits(word) {
+			total += p.weights[i]
+		}
+	}
+	if total == 0 {
+		return p.pick(r)
+	}
+	n := r.Intn(total)
+	for i, word := range p.words {
+		if !fits(word) {
+			continue
+		}
+		if n < p.weights[i] {
+			return word
+		}
+		n -= p.weights[i]
+	}
+	return p.pick(r)
+}
+
+// add appends a word to the pack with the given weight.
+func (p *wordPack) add(word string, weight int) {
+	p.words = append(p.words, word)
+	p.weights = append(p.weights, weight)
+	p.totalWeight += weight
+}
+
+// newWordLoader reads a word pack from an io.Reader.
+//
+// A pack starts with optional "# key: value" header lines naming and
+// describing it. Every other line holds whitespace separated words, optionally
+// followed by a tab and a weight that applies to each word on the line. Lines
+// starting with "#" after the header are comments.
+//
+// A pack with a "# mode: phrases" header treats each line as a single target,
+// so phrases and one-line code snippets can be typed including their spaces.
+func newWordLoader(r io.Reader) (*wordPack, error) {
+	pack := wordPack{}
 	scanner := bufio.NewScanner(r)
-	scanner.Split(bufio.ScanWords)
-	for scanner.Scan() {
-		words = append(words, scanner.Text())
+	inHeader := true
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			if inHeader {
+				key, value, _ := strings.Cut(strings.TrimPrefix(line, "#"), ":")
+				switch strings.ToLower(strings.TrimSpace(key)) {
+				case "name":
+					pack.name = strings.TrimSpace(value)
+				case "description":
+					pack.description = strings.TrimSpace(value)
+				case "mode":
+					pack.phrases = strings.TrimSpace(value) == "phrases"
+				}
+			}
+			continue
+		}
+		inHeader = false
+
+		words, weightStr, hasWeight := strings.Cut(line, "\t")
+		weight := 1
+		if hasWeight && strings.TrimSpace(weightStr) != "" {
+			var err error
+			weight, err = strconv.Atoi(strings.TrimSpace(weightStr))
+			if err != nil || weight < 1 {
+				return nil, fmt.Errorf("line %d: invalid weight %q", n, weightStr)
+			}
+		}
+		if pack.phrases {
+			if phrase := strings.Join(strings.Fields(words), " "); phrase != "" {
+				pack.add(phrase, weight)
+			}
+			continue
+		}
+		for _, w := range strings.Fields(words) {
+			pack.add(w, weight)
+		}
 	}
 
-	return words
+	return &pack, scanner.Err()
+}
+
+// loadWordFile reads a word pack from a single file, naming it after the file if it has no name.
+func loadWordFile(p string) (*wordPack, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	pack, err := newWordLoader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", p, err)
+	}
+	if pack.name == "" {
+		pack.name = strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+	}
+	return pack, nil
+}
+
+// loadWords reads a word pack from every path, which may be a file or a
+// directory whose regular, non-hidden files are all word packs.
+func loadWords(paths []string) ([]*wordPack, error) {
+	var packs []*wordPack
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			pack, err := loadWordFile(p)
+			if err != nil {
+				return nil, err
+			}
+			packs = append(packs, pack)
+			continue
+		}
+
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.Type().IsRegular() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			pack, err := loadWordFile(filepath.Join(p, e.Name()))
+			if err != nil {
+				return nil, err
+			}
+			packs = append(packs, pack)
+		}
+	}
+	return packs, nil
+}
+
+// loadDefaultWords reads the word list and packs shipped in the data directory.
+func loadDefaultWords() ([]*wordPack, error) {
+	names := []string{"words.txt"}
+	entries, err := readDataDir("packs")
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, path.Join("packs", e.Name()))
+		}
+	}
+
+	var packs []*wordPack
+	for _, name := range names {
+		data, err := readData(name)
+		if err != nil {
+			return nil, err
+		}
+		pack, err := newWordLoader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		if pack.name == "" {
+			pack.name = strings.TrimSuffix(path.Base(name), path.Ext(name))
+		}
+		packs = append(packs, pack)
+	}
+	return packs, nil
 }